@@ -3,6 +3,7 @@ module jam-bot
 go 1.22.2
 
 require (
+	github.com/alicebob/miniredis/v2 v2.31.1
 	github.com/bwmarrin/discordgo v0.28.1
 	github.com/gorilla/mux v1.8.1
 	github.com/joho/godotenv v1.5.1
@@ -11,6 +12,11 @@ require (
 	golang.org/x/oauth2 v0.18.0
 )
 
+require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
+	github.com/yuin/gopher-lua v1.1.0 // indirect
+)
+
 require (
 	github.com/cespare/xxhash/v2 v2.1.2 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
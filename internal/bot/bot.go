@@ -2,6 +2,7 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"jam-bot/internal/commands"
 	"jam-bot/internal/config"
@@ -10,7 +11,6 @@ import (
 	"log"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 
 	"github.com/bwmarrin/discordgo"
@@ -42,7 +42,10 @@ func StartBot() error {
 	}
 
 	// Initialize Spotify service with sendDM function
-	spotifyService := spotify.NewSpotifyService(cfg, SendDM)
+	spotifyService, err := spotify.NewSpotifyService(cfg, SendDM)
+	if err != nil {
+		return fmt.Errorf("failed to initialize Spotify service: %w", err)
+	}
 
 	// Start the unified HTTP server
 	go server.StartServer(cfg, spotifyService)
@@ -58,17 +61,48 @@ func StartBot() error {
 	cmdRegistry.Register(commands.NewHelpCommand(cmdRegistry))
 	cmdRegistry.Register(commands.NewSpotifyAuthCommand(spotifyService))
 	cmdRegistry.Register(commands.NewSpotifyStatusCommand(spotifyService))
+	cmdRegistry.Register(commands.NewStartCommand(spotifyService))
 	cmdRegistry.Register(commands.NewJoinCommand(spotifyService))
 	cmdRegistry.Register(commands.NewLeaveCommand(spotifyService))
 	cmdRegistry.Register(commands.NewAddCommand(spotifyService))
-	cmdRegistry.Register(commands.NewQueueCommand(spotifyService))
+	search := commands.NewSearchCommand(spotifyService, cmdRegistry)
+	cmdRegistry.Register(search)
+	queue := commands.NewQueueCommand(spotifyService)
+	cmdRegistry.Register(queue)
 	cmdRegistry.Register(commands.NewUsersCommand(spotifyService))
 	cmdRegistry.Register(commands.NewPlayCommand(spotifyService))
 	cmdRegistry.Register(commands.NewPauseCommand(spotifyService))
 	cmdRegistry.Register(commands.NewRemoveCommand(spotifyService))
+	cmdRegistry.Register(commands.NewMoveCommand(spotifyService))
+	cmdRegistry.Register(commands.NewSwapCommand(spotifyService))
+	cmdRegistry.Register(commands.NewPlayNextCommand(spotifyService))
+	cmdRegistry.Register(commands.NewClearCommand(spotifyService))
+	cmdRegistry.Register(commands.NewSkipCommand(spotifyService))
+	cmdRegistry.Register(commands.NewNextCommand(spotifyService))
+	cmdRegistry.Register(commands.NewPreviousCommand(spotifyService))
+	cmdRegistry.Register(commands.NewVoteSkipCommand(spotifyService))
+	cmdRegistry.Register(commands.NewSeekCommand(spotifyService))
+	cmdRegistry.Register(commands.NewShuffleCommand(spotifyService))
+	cmdRegistry.Register(commands.NewRepeatCommand(spotifyService))
+	cmdRegistry.Register(commands.NewFairCommand(spotifyService))
+	cmdRegistry.Register(commands.NewPolicyCommand(spotifyService))
+	cmdRegistry.Register(commands.NewSyncStatsCommand(spotifyService))
+	cmdRegistry.Register(commands.NewDevicesCommand(spotifyService))
+	cmdRegistry.Register(commands.NewDeviceCommand(spotifyService))
+
+	// Keep the now playing panel in each session channel up to date
+	panel := commands.NewPanel(dg, cmdRegistry)
+	cmdRegistry.Register(commands.NewNowPlayingCommand(spotifyService, panel))
+	cmdRegistry.Register(commands.NewEndCommand(spotifyService, panel))
+	spotifyService.OnPlaybackChange = func(session *spotify.Session) {
+		err := panel.Update(session)
+		if err != nil {
+			log.Printf("[ERROR] Failed to update now playing panel for channel %s: %v", session.ChannelID, err)
+		}
+	}
 
 	// Load and validate existing sessions
-	err = loadAndValidateSessions(spotifyService)
+	sessions, err := loadAndValidateSessions(spotifyService)
 	if err != nil {
 		return fmt.Errorf("failed to load and validate sessions: %w", err)
 	}
@@ -80,29 +114,54 @@ func StartBot() error {
 			return
 		}
 
-		// Check if the message starts with the bot prefix
-		if !strings.HasPrefix(m.Content, cfg.BotPrefix) {
+		// Parse and execute the command
+		err := cmdRegistry.ExecuteCommand(s, m, cfg.BotPrefix)
+		if errors.Is(err, commands.ErrCommandNotFound) {
 			return
 		}
-
-		// Parse the command and arguments
-		args := strings.Fields(m.Content[len(cfg.BotPrefix):])
-		if len(args) == 0 {
-			return
+		if err != nil {
+			log.Printf("[ERROR] Command execution failed: %v", err)
+			_, sendErr := s.ChannelMessageSend(m.ChannelID, "❌ An error occurred while executing the command.")
+			if sendErr != nil {
+				log.Printf("[ERROR] Failed to send error message: %v", sendErr)
+			}
 		}
-		cmdName := strings.ToLower(args[0])
-		cmdArgs := args[1:]
+	})
 
-		// Find and execute the command
-		cmd, exists := cmdRegistry.Get(cmdName)
-		if exists != nil {
+	// Add slash command, autocomplete, button and menu handler
+	dg.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		var err error
+		switch i.Type {
+		case discordgo.InteractionApplicationCommand:
+			err = cmdRegistry.ExecuteInteraction(s, i)
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			err = cmdRegistry.Autocomplete(s, i)
+			if err != nil {
+				// There is no deferred response to follow up on
+				log.Printf("[ERROR] Autocomplete failed: %v", err)
+			}
+			return
+		case discordgo.InteractionMessageComponent:
+			customID := i.MessageComponentData().CustomID
+			switch {
+			case panel.HandlesComponent(customID):
+				err = panel.HandleComponent(s, i)
+			case search.HandlesComponent(customID):
+				err = search.HandleComponent(s, i)
+			case queue.HandlesComponent(customID):
+				err = queue.HandleComponent(s, i)
+			default:
+				return
+			}
+		default:
 			return
 		}
 
-		err := cmd.Execute(s, m, cmdArgs)
 		if err != nil {
-			log.Printf("[ERROR] Command execution failed: %v", err)
-			_, sendErr := s.ChannelMessageSend(m.ChannelID, "❌ An error occurred while executing the command.")
+			log.Printf("[ERROR] Interaction handling failed: %v", err)
+			_, sendErr := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+				Content: "❌ An error occurred while executing the command.",
+			})
 			if sendErr != nil {
 				log.Printf("[ERROR] Failed to send error message: %v", sendErr)
 			}
@@ -114,6 +173,15 @@ func StartBot() error {
 	if err != nil {
 		return fmt.Errorf("error opening Discord session: %w", err)
 	}
+
+	// Rebuild the timers that advance each session's queue, now that panel updates can be sent
+	spotifyService.ResumeScheduling(sessions)
+
+	// Register every command as a global slash command, replacing stale ones
+	_, err = dg.ApplicationCommandBulkOverwrite(dg.State.User.ID, "", cmdRegistry.ApplicationCommands())
+	if err != nil {
+		log.Printf("[WARN] Failed to register slash commands: %v", err)
+	}
 	log.Println("[INFO] bot is now running. Press CTRL+C to exit.")
 
 	// Wait until CTRL+C or other termination signal is received
@@ -131,12 +199,13 @@ func StartBot() error {
 	return nil
 }
 
-// loadAndValidateSessions loads all existing sessions and validates participant authentication
-func loadAndValidateSessions(spotifyService *spotify.Service) error {
+// loadAndValidateSessions loads all existing sessions and validates participant authentication,
+// returning the sessions whose scheduling should be resumed
+func loadAndValidateSessions(spotifyService *spotify.Service) ([]spotify.Session, error) {
 	ctx := context.Background()
 	sessions, err := spotifyService.LoadAllSessions(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to load sessions: %w", err)
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
 	}
 
 	for _, session := range sessions {
@@ -157,6 +226,9 @@ func loadAndValidateSessions(spotifyService *spotify.Service) error {
 		}
 	}
 
+	// Fill in album, artwork and duration for songs queued by older versions of the bot
+	spotifyService.BackfillMetadata(ctx, sessions)
+
 	log.Println("[INFO] All sessions loaded and validated successfully.")
-	return nil
+	return sessions, nil
 }
@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"jam-bot/internal/spotify"
 	"strings"
@@ -22,15 +22,26 @@ func (c *AddCommand) Name() string {
 }
 
 func (c *AddCommand) Description() string {
-	return "Adds a song to the jam session queue. Usage: !add [song name]"
+	return "Adds a song, or every song of a Spotify album or playlist link, to the jam session queue. Usage: !add [song name | Spotify link]"
 }
 
-func (c *AddCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	channelID := m.ChannelID
+func (c *AddCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "song",
+			Description: "A song to search for, or a Spotify track, album or playlist link",
+			Required:    true,
+		},
+	}
+}
+
+func (c *AddCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
 
 	if channelID == "" {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ This command can only be used within a channel.")
+		err := ctx.Reply("❌ This command can only be used within a channel.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -38,7 +49,7 @@ func (c *AddCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, a
 	}
 
 	if len(args) == 0 {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ Please provide a song name. Usage: `!add [song name]`")
+		err := ctx.Reply("❌ Please provide a song name or Spotify link. Usage: `!add [song name | Spotify link]`")
 		if err != nil {
 			return fmt.Errorf("failed to send usage message: %w", err)
 		}
@@ -47,20 +58,46 @@ func (c *AddCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, a
 
 	songName := strings.Join(args, " ")
 
+	// Songs can only be queued into a running session, so don't go to Spotify without one
+	_, err := c.spotifyService.LoadSession(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	// Links resolve to exactly what they point to instead of a search result
+	if link, ok := spotify.ParseLink(songName); ok {
+		return c.addLink(ctx, channelID, link)
+	}
+
 	// Search for the song using Spotify API
-	song, err := c.spotifyService.SearchSong(ctx, m.Author.ID, songName)
+	song, err := c.spotifyService.SearchSong(ctx.Context(), ctx.Author().ID, songName)
 	if err != nil {
-		_, sendErr := s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("❌ Failed to find the song: %v", err))
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to find the song: %v", err))
 		if sendErr != nil {
 			return fmt.Errorf("failed to send error message: %w", sendErr)
 		}
 		return fmt.Errorf("failed to search song: %w", err)
 	}
 
+	song.AddedBy = ctx.Author().ID
+
 	// Add the song to the session queue
-	err = c.spotifyService.AddSongToQueue(ctx, channelID, song) // Updated to use channelID
+	err = c.spotifyService.AddSongToQueue(ctx.Context(), channelID, song) // Updated to use channelID
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if message, ok := policyRejection(err); ok {
+		err = ctx.Reply(message)
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
 	if err != nil {
-		_, sendErr := s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("❌ Failed to add the song to the queue: %v", err))
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to add the song to the queue: %v", err))
 		if sendErr != nil {
 			return fmt.Errorf("failed to send error message: %w", sendErr)
 		}
@@ -68,7 +105,62 @@ func (c *AddCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, a
 	}
 
 	// Confirm to the user
-	_, err = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✅ **%s** by **%s** has been added to the queue.", song.Title, song.Artist))
+	err = ctx.Reply(fmt.Sprintf("✅ **%s** by **%s** has been added to the queue.", song.Title, song.Artist))
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
+
+// addLink adds the track, album or playlist a Spotify link points to
+func (c *AddCommand) addLink(ctx Context, channelID string, link spotify.Link) error {
+	resolved, err := c.spotifyService.ResolveLink(ctx.Context(), ctx.Author().ID, link)
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to add the link: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to resolve link: %w", err)
+	}
+
+	for i := range resolved.Songs {
+		resolved.Songs[i].AddedBy = ctx.Author().ID
+	}
+
+	// Add the songs to the session queue
+	rejected, err := c.spotifyService.AddSongsToQueue(ctx.Context(), channelID, resolved.Songs)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to add the songs to the queue: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to add songs to queue: %w", err)
+	}
+
+	// Confirm to the user
+	var message string
+	switch {
+	case resolved.Kind == spotify.LinkTrack && len(rejected) > 0:
+		message, _ = policyRejection(rejected[0])
+	case resolved.Kind == spotify.LinkTrack:
+		song := resolved.Songs[0]
+		message = fmt.Sprintf("✅ **%s** by **%s** has been added to the queue.", song.Title, song.Artist)
+	default:
+		added := len(resolved.Songs) - len(rejected)
+		message = fmt.Sprintf("✅ Added **%d** songs from the %s **%s** to the queue.", added, resolved.Kind, resolved.Name)
+		if resolved.Truncated {
+			message += " Only the first songs were added; it was too long to add in full."
+		}
+		if len(rejected) > 0 {
+			message += "\n" + skippedByRule(rejected)
+		}
+	}
+
+	err = ctx.Reply(message)
 	if err != nil {
 		return fmt.Errorf("failed to send confirmation message: %w", err)
 	}
@@ -0,0 +1,126 @@
+package commands_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"jam-bot/internal/commands"
+	"jam-bot/internal/commands/commandstest"
+	"jam-bot/internal/spotify"
+)
+
+func TestAddCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		channel   string
+		args      []string
+		noSession bool
+		policy    spotify.QueuePolicy
+		wantReply string
+		wantQueue []string
+	}{
+		{
+			name:      "outside a channel",
+			args:      []string{"first"},
+			noSession: true,
+			wantReply: "can only be used within a channel",
+		},
+		{
+			name:      "no session",
+			channel:   "channel",
+			args:      []string{"first"},
+			noSession: true,
+			wantReply: "no jam session in this channel",
+		},
+		{
+			name:      "no song given",
+			channel:   "channel",
+			wantReply: "Usage:",
+			wantQueue: []string{},
+		},
+		{
+			name:      "song found",
+			channel:   "channel",
+			args:      []string{"second", "song"},
+			wantReply: "**Second Song** by **Band** has been added",
+			wantQueue: []string{"Second Song"},
+		},
+		{
+			name:      "song link",
+			channel:   "channel",
+			args:      []string{"https://open.spotify.com/track/third"},
+			wantReply: "**Third Song** by **Band** has been added",
+			wantQueue: []string{"Third Song"},
+		},
+		{
+			name:      "no results",
+			channel:   "channel",
+			args:      []string{"nothing", "like", "this"},
+			wantReply: "Failed to find the song",
+			wantQueue: []string{},
+		},
+		{
+			name:      "blocked by the queue policy",
+			channel:   "channel",
+			args:      []string{"rude"},
+			policy:    spotify.QueuePolicy{NoExplicit: true},
+			wantReply: "**Rude Song** wasn't added",
+			wantQueue: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			if !tt.noSession {
+				env.startSession(t)
+			}
+			err := env.svc.SetQueuePolicy(context.Background(), "guild", tt.policy)
+			if err != nil {
+				t.Fatalf("SetQueuePolicy: %v", err)
+			}
+			before := env.srv.Requests()
+
+			ctx := commandstest.NewContext(guestID, tt.channel, tt.args...)
+			err = commands.NewAddCommand(env.svc).Execute(ctx)
+
+			if !strings.Contains(ctx.LastReply(), tt.wantReply) {
+				t.Errorf("reply = %q, want it to contain %q", ctx.LastReply(), tt.wantReply)
+			}
+			if tt.noSession {
+				if err != nil {
+					t.Errorf("Execute: %v", err)
+				}
+				// Without a session there is nothing to add to, so Spotify isn't searched
+				if sent := env.srv.Requests() - before; sent != 0 {
+					t.Errorf("%d requests were sent to Spotify, want none", sent)
+				}
+				return
+			}
+			if queue := env.queue(t); !reflect.DeepEqual(queue, tt.wantQueue) {
+				t.Errorf("queue = %v, want %v", queue, tt.wantQueue)
+			}
+		})
+	}
+}
+
+func TestAddCommandRecordsRequester(t *testing.T) {
+	env := newTestEnv(t)
+	env.startSession(t)
+
+	ctx := commandstest.NewContext(guestID, "channel", "first")
+	err := commands.NewAddCommand(env.svc).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	session, err := env.svc.LoadSession(context.Background(), "channel")
+	if err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	if len(session.Queue) != 1 || session.Queue[0].AddedBy != guestID {
+		t.Errorf("queue = %+v, want one song added by %s", session.Queue, guestID)
+	}
+}
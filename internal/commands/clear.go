@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type ClearCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewClearCommand(spotifyService *spotify.Service) *ClearCommand {
+	return &ClearCommand{spotifyService: spotifyService}
+}
+
+func (c *ClearCommand) Name() string {
+	return "clear"
+}
+
+func (c *ClearCommand) Description() string {
+	return "Removes every upcoming song from the queue. The current song keeps playing."
+}
+
+func (c *ClearCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *ClearCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	// Clear the upcoming songs
+	cleared, err := c.spotifyService.ClearQueue(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to clear the queue: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to clear queue: %w", err)
+	}
+
+	// Confirm to the user
+	err = ctx.Reply(fmt.Sprintf("🧹 Removed **%d** upcoming song(s) from the queue.", cleared))
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
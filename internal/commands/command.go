@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -10,6 +12,14 @@ type Command interface {
 	Name() string
 	// Description returns the command description
 	Description() string
-	// Execute runs the command with the given session and message
-	Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
+	// Options describes the command arguments, in the order they appear in Context.Args
+	Options() []*discordgo.ApplicationCommandOption
+	// Execute runs the command for the given invocation
+	Execute(ctx Context) error
+}
+
+// Autocompleter is implemented by commands that suggest values for options marked Autocomplete
+type Autocompleter interface {
+	// Autocomplete returns suggestions for the named option given what the user has typed so far
+	Autocomplete(ctx context.Context, userID, option, value string) ([]*discordgo.ApplicationCommandOptionChoice, error)
 }
@@ -0,0 +1,107 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	"jam-bot/internal/config"
+	"jam-bot/internal/spotify"
+	"jam-bot/internal/spotify/spotifytest"
+)
+
+// Discord IDs of the test users; they log in to the fake Spotify users "host" and "guest"
+const (
+	hostID  = "111"
+	guestID = "222"
+)
+
+// testEnv is a Spotify service backed by the memory store and the fake Spotify server
+type testEnv struct {
+	svc   *spotify.Service
+	srv   *spotifytest.Server
+	store *spotify.MemoryStore
+}
+
+// newTestEnv starts a fake Spotify server with a small catalog and logs the test users in to it
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	srv := spotifytest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddTracks(
+		spotifytest.Track{Name: "First Song", Artists: []string{"Band"}, URI: "spotify:track:first", DurationMs: 60000},
+		spotifytest.Track{Name: "Second Song", Artists: []string{"Band"}, URI: "spotify:track:second", DurationMs: 60000},
+		spotifytest.Track{Name: "Third Song", Artists: []string{"Band"}, URI: "spotify:track:third", DurationMs: 60000},
+		spotifytest.Track{Name: "Rude Song", Artists: []string{"Band"}, URI: "spotify:track:rude", DurationMs: 60000, Explicit: true},
+	)
+
+	cfg := &config.Config{
+		SpotifyClientID:     "client-id",
+		SpotifyClientSecret: "client-secret",
+		SpotifyRedirectURI:  "http://localhost/callback",
+	}
+	srv.Configure(cfg)
+	store := spotify.NewMemoryStore()
+	svc := spotify.NewSpotifyServiceWithStores(cfg, store, store, store, nil)
+
+	// The state is the Discord user; the code names the fake Spotify user
+	for discordID, code := range map[string]string{hostID: "host", guestID: "guest"} {
+		err := svc.HandleCallback(context.Background(), discordID, code)
+		if err != nil {
+			t.Fatalf("HandleCallback(%s): %v", discordID, err)
+		}
+	}
+
+	return &testEnv{svc: svc, srv: srv, store: store}
+}
+
+// startSession starts a session in "channel" hosted by the host, with the guest joined and
+// the given songs queued, added in turn by the host and the guest
+func (env *testEnv) startSession(t *testing.T, songs ...spotify.Song) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := env.svc.StartSession(ctx, "guild", "channel", hostID, spotify.SessionSettings{})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	t.Cleanup(func() { env.svc.DeleteSession(ctx, "channel") })
+
+	err = env.svc.AddUserToSession(ctx, "guild", "channel", guestID)
+	if err != nil {
+		t.Fatalf("AddUserToSession: %v", err)
+	}
+
+	for i, song := range songs {
+		song.AddedBy = hostID
+		if i%2 == 1 {
+			song.AddedBy = guestID
+		}
+		err = env.svc.AddSongToQueue(ctx, "channel", song)
+		if err != nil {
+			t.Fatalf("AddSongToQueue(%s): %v", song.Title, err)
+		}
+	}
+}
+
+// queue returns the titles queued in "channel"
+func (env *testEnv) queue(t *testing.T) []string {
+	t.Helper()
+
+	session, err := env.svc.LoadSession(context.Background(), "channel")
+	if err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	titles := make([]string, len(session.Queue))
+	for i, song := range session.Queue {
+		titles[i] = song.Title
+	}
+	return titles
+}
+
+// testSongs are the fake catalog's songs as they are queued
+var testSongs = []spotify.Song{
+	{Title: "First Song", Artist: "Band", URI: "spotify:track:first", DurationMs: 60000},
+	{Title: "Second Song", Artist: "Band", URI: "spotify:track:second", DurationMs: 60000},
+	{Title: "Third Song", Artist: "Band", URI: "spotify:track:third", DurationMs: 60000},
+}
@@ -0,0 +1,114 @@
+// Package commandstest provides an in-memory commands.Context for exercising commands without Discord.
+package commandstest
+
+import (
+	"context"
+	"jam-bot/internal/commands"
+)
+
+// Context is a commands.Context that records everything a command sends
+type Context struct {
+	Ctx       context.Context
+	User      commands.User
+	Channel   string
+	Guild     string
+	Arguments []string
+
+	// ManageServer is returned by CanManageServer
+	ManageServer bool
+
+	Replies []string            // Messages sent with Reply, in order
+	Embeds  []commands.Embed    // Embeds sent with ReplyEmbed, ReplyMenu or ReplyButtons, in order
+	Menus   []commands.Menu     // Menus sent with ReplyMenu, in order
+	Buttons [][]commands.Button // Button rows sent with ReplyButtons, in order
+	DMs     []string            // Direct messages sent to the author, in order
+
+	// SendErr, if set, is returned by every send method instead of recording the message
+	SendErr error
+}
+
+// NewContext creates a fake invocation of a command by a user in a channel
+func NewContext(userID, channelID string, args ...string) *Context {
+	return &Context{
+		Ctx:       context.Background(),
+		User:      commands.User{ID: userID, Username: userID},
+		Channel:   channelID,
+		Arguments: args,
+	}
+}
+
+func (c *Context) Context() context.Context {
+	return c.Ctx
+}
+
+func (c *Context) Author() commands.User {
+	return c.User
+}
+
+func (c *Context) ChannelID() string {
+	return c.Channel
+}
+
+func (c *Context) GuildID() string {
+	return c.Guild
+}
+
+func (c *Context) CanManageServer() bool {
+	return c.ManageServer
+}
+
+func (c *Context) Args() []string {
+	return c.Arguments
+}
+
+func (c *Context) Reply(content string) error {
+	if c.SendErr != nil {
+		return c.SendErr
+	}
+	c.Replies = append(c.Replies, content)
+	return nil
+}
+
+func (c *Context) ReplyEmbed(embed commands.Embed) error {
+	if c.SendErr != nil {
+		return c.SendErr
+	}
+	c.Embeds = append(c.Embeds, embed)
+	return nil
+}
+
+func (c *Context) ReplyMenu(embed commands.Embed, menu commands.Menu) error {
+	if c.SendErr != nil {
+		return c.SendErr
+	}
+	c.Embeds = append(c.Embeds, embed)
+	c.Menus = append(c.Menus, menu)
+	return nil
+}
+
+func (c *Context) ReplyButtons(embed commands.Embed, buttons []commands.Button) error {
+	if c.SendErr != nil {
+		return c.SendErr
+	}
+	c.Embeds = append(c.Embeds, embed)
+	c.Buttons = append(c.Buttons, buttons)
+	return nil
+}
+
+func (c *Context) DM(content string) error {
+	if c.SendErr != nil {
+		return c.SendErr
+	}
+	c.DMs = append(c.DMs, content)
+	return nil
+}
+
+// LastReply returns the most recent channel reply, or "" if there is none
+func (c *Context) LastReply() string {
+	if len(c.Replies) == 0 {
+		return ""
+	}
+	return c.Replies[len(c.Replies)-1]
+}
+
+var _ commands.Context = (*Context)(nil)
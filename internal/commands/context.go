@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+)
+
+// User identifies whoever invoked a command
+type User struct {
+	ID       string
+	Username string
+}
+
+// Mention returns the chat mention for the user
+func (u User) Mention() string {
+	return "<@" + u.ID + ">"
+}
+
+// EmbedField is a titled section of an Embed
+type EmbedField struct {
+	Name   string
+	Value  string
+	Inline bool
+}
+
+// Embed is a rich message, independent of the chat platform that renders it
+type Embed struct {
+	Title       string
+	Description string
+	Fields      []EmbedField
+	Footer      string
+}
+
+// MenuOption is one entry of a Menu
+type MenuOption struct {
+	Label       string
+	Description string
+	Value       string // Returned to the menu's handler when the option is picked
+}
+
+// Menu is a dropdown of options sent below an embed. Picks are delivered as message component
+// interactions carrying the menu's ID, so whoever sends a menu must also handle its ID.
+type Menu struct {
+	ID          string
+	Placeholder string
+	Options     []MenuOption
+}
+
+// Button is a clickable button sent below an embed. Clicks are delivered as message component
+// interactions carrying the button's ID, so whoever sends a button must also handle its ID.
+type Button struct {
+	ID       string
+	Label    string
+	Emoji    string
+	Disabled bool
+}
+
+// Context carries everything a command needs about its invocation and how to answer it.
+// The Discord implementation is built by the Registry; commandstest provides an in-memory fake.
+type Context interface {
+	// Context returns the context.Context the command's work should run under
+	Context() context.Context
+	// Author returns the user who invoked the command
+	Author() User
+	// ChannelID returns the channel the command was invoked in
+	ChannelID() string
+	// GuildID returns the guild the command was invoked in, or "" for direct messages
+	GuildID() string
+	// CanManageServer reports whether the author may change the guild's settings
+	CanManageServer() bool
+	// Args returns the command arguments
+	Args() []string
+	// Reply sends a message to wherever the command was invoked from
+	Reply(content string) error
+	// ReplyEmbed sends a rich message to wherever the command was invoked from
+	ReplyEmbed(embed Embed) error
+	// ReplyMenu sends a rich message with a dropdown menu to wherever the command was invoked from
+	ReplyMenu(embed Embed, menu Menu) error
+	// ReplyButtons sends a rich message with a row of buttons to wherever the command was invoked from
+	ReplyButtons(embed Embed, buttons []Button) error
+	// DM sends a direct message to the author
+	DM(content string) error
+}
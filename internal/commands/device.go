@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// autoDeviceName clears the preferred device so playback follows the active device
+const autoDeviceName = "auto"
+
+type DeviceCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewDeviceCommand(spotifyService *spotify.Service) *DeviceCommand {
+	return &DeviceCommand{spotifyService: spotifyService}
+}
+
+func (c *DeviceCommand) Name() string {
+	return "device"
+}
+
+func (c *DeviceCommand) Description() string {
+	return "Chooses the Spotify device jam sessions play on for you, or \"auto\" for your active device."
+}
+
+func (c *DeviceCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "name",
+			Description: "Name of the device, or \"auto\"",
+			Required:    true,
+		},
+	}
+}
+
+func (c *DeviceCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	userID := ctx.Author().ID
+
+	if len(args) == 0 {
+		err := ctx.Reply("❌ Please provide a device name. Usage: `!device [name]` or `!device auto`")
+		if err != nil {
+			return fmt.Errorf("failed to send usage message: %w", err)
+		}
+		return nil
+	}
+
+	name := strings.Join(args, " ")
+
+	if strings.EqualFold(name, autoDeviceName) {
+		err := c.spotifyService.ClearPreferredDevice(ctx.Context(), userID)
+		if err != nil {
+			return fmt.Errorf("failed to clear preferred device: %w", err)
+		}
+
+		err = ctx.Reply("✅ Jam sessions will play on your active Spotify device.")
+		if err != nil {
+			return fmt.Errorf("failed to send confirmation message: %w", err)
+		}
+		return nil
+	}
+
+	device, err := c.spotifyService.SetPreferredDevice(ctx.Context(), userID, name)
+	if err != nil {
+		var message string
+		switch {
+		case errors.Is(err, spotify.ErrTokenNotFound):
+			message = "❌ You need to authenticate with Spotify first. Use `!auth` to authenticate."
+		case errors.Is(err, spotify.ErrDeviceNotFound):
+			message = fmt.Sprintf("❌ No device named **%s**. Use `!devices` to see your devices.", name)
+		case errors.Is(err, spotify.ErrAmbiguousDevice):
+			message = fmt.Sprintf("❌ More than one device matches **%s**. Please use the full name from `!devices`.", name)
+		default:
+			message = fmt.Sprintf("❌ Failed to choose device: %v", err)
+		}
+
+		sendErr := ctx.Reply(message)
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return nil
+	}
+
+	err = ctx.Reply(fmt.Sprintf("✅ Jam sessions will play on **%s**.", device.Name))
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
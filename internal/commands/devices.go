@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type DevicesCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewDevicesCommand(spotifyService *spotify.Service) *DevicesCommand {
+	return &DevicesCommand{spotifyService: spotifyService}
+}
+
+func (c *DevicesCommand) Name() string {
+	return "devices"
+}
+
+func (c *DevicesCommand) Description() string {
+	return "Lists your Spotify devices and which one jam sessions play on."
+}
+
+func (c *DevicesCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *DevicesCommand) Execute(ctx Context) error {
+	userID := ctx.Author().ID
+
+	devices, err := c.spotifyService.GetUserDevices(ctx.Context(), userID)
+	if errors.Is(err, spotify.ErrTokenNotFound) {
+		err = ctx.Reply("❌ You need to authenticate with Spotify first. Use `!auth` to authenticate.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get devices: %w", err)
+	}
+
+	if len(devices) == 0 {
+		err = ctx.Reply("No Spotify devices found. Please open Spotify on one of your devices.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	preferred, err := c.spotifyService.PreferredDevice(ctx.Context(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to get preferred device: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, device := range devices {
+		sb.WriteString(fmt.Sprintf("**%s** (%s, volume %d%%)", device.Name, device.Type, device.VolumePercent))
+		if preferred != nil && (device.ID == preferred.ID || strings.EqualFold(device.Name, preferred.Name)) {
+			sb.WriteString(" ⭐ preferred")
+		}
+		if device.IsActive {
+			sb.WriteString(" 🟢 active")
+		}
+		if device.IsRestricted {
+			sb.WriteString(" 🚫 restricted")
+		}
+		sb.WriteString("\n")
+	}
+
+	footer := "Use !device [name] to choose where jam sessions play."
+	if preferred != nil {
+		footer = "Use !device auto to go back to your active device."
+	}
+
+	err = ctx.ReplyEmbed(Embed{
+		Title:       "🔈 Your Spotify Devices",
+		Description: sb.String(),
+		Footer:      footer,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send devices: %w", err)
+	}
+
+	return nil
+}
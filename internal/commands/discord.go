@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discordContext is the Context for commands invoked from Discord, by chat message or interaction
+type discordContext struct {
+	ctx       context.Context
+	session   *discordgo.Session
+	author    User
+	channelID string
+	guildID   string
+	args      []string
+
+	interaction *discordgo.Interaction
+	replied     bool
+}
+
+// newMessageContext creates a Context for a prefixed chat message
+func newMessageContext(s *discordgo.Session, m *discordgo.MessageCreate, args []string) *discordContext {
+	return &discordContext{
+		ctx:       context.Background(),
+		session:   s,
+		author:    User{ID: m.Author.ID, Username: m.Author.Username},
+		channelID: m.ChannelID,
+		guildID:   m.GuildID,
+		args:      args,
+	}
+}
+
+// newInteractionContext creates a Context for a slash command or component interaction
+func newInteractionContext(s *discordgo.Session, i *discordgo.InteractionCreate, args []string) *discordContext {
+	return &discordContext{
+		ctx:         context.Background(),
+		session:     s,
+		author:      interactionUser(i),
+		channelID:   i.ChannelID,
+		guildID:     i.GuildID,
+		args:        args,
+		interaction: i.Interaction,
+	}
+}
+
+// interactionUser returns the user who triggered an interaction
+func interactionUser(i *discordgo.InteractionCreate) User {
+	// Guild interactions carry the user on the member, DM interactions carry it directly
+	user := i.User
+	if i.Member != nil {
+		user = i.Member.User
+	}
+	return User{ID: user.ID, Username: user.Username}
+}
+
+func (c *discordContext) Context() context.Context {
+	return c.ctx
+}
+
+func (c *discordContext) Author() User {
+	return c.author
+}
+
+func (c *discordContext) ChannelID() string {
+	return c.channelID
+}
+
+func (c *discordContext) GuildID() string {
+	return c.guildID
+}
+
+func (c *discordContext) CanManageServer() bool {
+	if c.guildID == "" {
+		return false
+	}
+
+	// Interactions carry the member's permissions; messages need them worked out from the guild
+	var permissions int64
+	if c.interaction != nil && c.interaction.Member != nil {
+		permissions = c.interaction.Member.Permissions
+	} else {
+		var err error
+		permissions, err = c.session.UserChannelPermissions(c.author.ID, c.channelID)
+		if err != nil {
+			log.Printf("[WARN] Failed to get permissions of user %s in channel %s: %v", c.author.ID, c.channelID, err)
+			return false
+		}
+	}
+
+	return permissions&(discordgo.PermissionManageServer|discordgo.PermissionAdministrator) != 0
+}
+
+func (c *discordContext) Args() []string {
+	return c.args
+}
+
+func (c *discordContext) Reply(content string) error {
+	return c.send(&discordgo.MessageSend{Content: content})
+}
+
+func (c *discordContext) ReplyEmbed(embed Embed) error {
+	return c.send(&discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{toDiscordEmbed(embed)}})
+}
+
+func (c *discordContext) ReplyMenu(embed Embed, menu Menu) error {
+	return c.send(&discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{toDiscordEmbed(embed)},
+		Components: toDiscordMenu(menu),
+	})
+}
+
+func (c *discordContext) ReplyButtons(embed Embed, buttons []Button) error {
+	return c.send(&discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{toDiscordEmbed(embed)},
+		Components: toDiscordButtons(buttons),
+	})
+}
+
+func (c *discordContext) DM(content string) error {
+	dm, err := c.session.UserChannelCreate(c.author.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create DM channel: %w", err)
+	}
+
+	_, err = c.session.ChannelMessageSend(dm.ID, content)
+	return err
+}
+
+// send posts a message in the channel, or as an interaction followup
+func (c *discordContext) send(msg *discordgo.MessageSend) error {
+	c.replied = true
+
+	// Interactions are deferred before the command runs, so replies are sent as followups
+	if c.interaction != nil {
+		_, err := c.session.FollowupMessageCreate(c.interaction, true, &discordgo.WebhookParams{
+			Content:    msg.Content,
+			Embeds:     msg.Embeds,
+			Components: msg.Components,
+		})
+		return err
+	}
+
+	_, err := c.session.ChannelMessageSendComplex(c.channelID, msg)
+	return err
+}
+
+// toDiscordEmbed converts an Embed into its Discord representation
+func toDiscordEmbed(embed Embed) *discordgo.MessageEmbed {
+	fields := make([]*discordgo.MessageEmbedField, 0, len(embed.Fields))
+	for _, field := range embed.Fields {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   field.Name,
+			Value:  field.Value,
+			Inline: field.Inline,
+		})
+	}
+
+	discordEmbed := &discordgo.MessageEmbed{
+		Title:       embed.Title,
+		Description: embed.Description,
+		Fields:      fields,
+	}
+	if embed.Footer != "" {
+		discordEmbed.Footer = &discordgo.MessageEmbedFooter{Text: embed.Footer}
+	}
+	return discordEmbed
+}
+
+// toDiscordMenu converts a Menu into a row holding a Discord select menu
+func toDiscordMenu(menu Menu) []discordgo.MessageComponent {
+	options := make([]discordgo.SelectMenuOption, 0, len(menu.Options))
+	for _, option := range menu.Options {
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       option.Label,
+			Description: option.Description,
+			Value:       option.Value,
+		})
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					CustomID:    menu.ID,
+					Placeholder: menu.Placeholder,
+					Options:     options,
+				},
+			},
+		},
+	}
+}
+
+// toDiscordButtons converts Buttons into a row of Discord buttons
+func toDiscordButtons(buttons []Button) []discordgo.MessageComponent {
+	row := make([]discordgo.MessageComponent, 0, len(buttons))
+	for _, button := range buttons {
+		discordButton := discordgo.Button{
+			Label:    button.Label,
+			Style:    discordgo.SecondaryButton,
+			CustomID: button.ID,
+			Disabled: button.Disabled,
+		}
+		if button.Emoji != "" {
+			discordButton.Emoji = &discordgo.ComponentEmoji{Name: button.Emoji}
+		}
+		row = append(row, discordButton)
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: row},
+	}
+}
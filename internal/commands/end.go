@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxSummaryRequesters is the number of top requesters listed in the end of session summary
+const maxSummaryRequesters = 3
+
+type EndCommand struct {
+	spotifyService *spotify.Service
+	panel          *Panel
+}
+
+func NewEndCommand(spotifyService *spotify.Service, panel *Panel) *EndCommand {
+	return &EndCommand{spotifyService: spotifyService, panel: panel}
+}
+
+func (c *EndCommand) Name() string {
+	return "end"
+}
+
+func (c *EndCommand) Description() string {
+	return "Ends the jam session in this channel, pausing everyone. Only the host or a server manager can end it."
+}
+
+func (c *EndCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *EndCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+	userID := ctx.Author().ID
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	session, err := c.spotifyService.LoadSession(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	if !canEndSession(ctx, session) {
+		message := "❌ Only someone in this jam session or a server manager can end it."
+		if session.Host != "" {
+			message = fmt.Sprintf("❌ Only the host, <@%s>, or a server manager can end this jam session.", session.Host)
+		}
+		err = ctx.Reply(message)
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	ended, err := c.spotifyService.EndSession(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		// Someone else ended it first
+		return promptStart(ctx)
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to end the jam session: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+
+	c.panel.Remove(ended)
+
+	err = ctx.ReplyEmbed(sessionSummary(ended, userID, time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to send session summary: %w", err)
+	}
+
+	return nil
+}
+
+// canEndSession reports whether the author may end a session: its host, a server manager,
+// or any participant of a session started before hosts were recorded
+func canEndSession(ctx Context, session *spotify.Session) bool {
+	userID := ctx.Author().ID
+	if session.Host == userID || ctx.CanManageServer() {
+		return true
+	}
+	if session.Host != "" {
+		return false
+	}
+	for _, id := range session.Participants {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionSummary describes a session that endedBy just ended
+func sessionSummary(session *spotify.Session, endedBy string, now time.Time) Embed {
+	var fields []EmbedField
+	if session.Host != "" {
+		fields = append(fields, EmbedField{Name: "Host", Value: "<@" + session.Host + ">", Inline: true})
+	}
+	if createdAt := session.CreatedAt(); !createdAt.IsZero() {
+		length := int(now.Sub(createdAt).Milliseconds())
+		fields = append(fields, EmbedField{Name: "Length", Value: formatDuration(length), Inline: true})
+	}
+	fields = append(fields,
+		EmbedField{Name: "Songs played", Value: fmt.Sprint(session.PlayedCount), Inline: true},
+		EmbedField{Name: "Listeners", Value: fmt.Sprint(len(session.Participants)), Inline: true},
+	)
+	if requesters := topRequesters(session.History); requesters != "" {
+		fields = append(fields, EmbedField{Name: "Top requesters", Value: requesters})
+	}
+
+	description := fmt.Sprintf("<@%s> ended the jam session. Thanks for listening!", endedBy)
+	if session.Playback.IsPlaying {
+		description = fmt.Sprintf("<@%s> ended the jam session and paused playback. Thanks for listening!", endedBy)
+	}
+	upcoming := len(session.Queue)
+	if upcoming > 0 && session.Playback.CurrentSong.URI != "" && session.Queue[0].URI == session.Playback.CurrentSong.URI {
+		upcoming--
+	}
+	if upcoming > 0 {
+		description += fmt.Sprintf("\n%d queued songs went unplayed.", upcoming)
+	}
+
+	return Embed{
+		Title:       "🏁 Jam Session Ended",
+		Description: description,
+		Fields:      fields,
+		Footer:      "Start a new one any time with !start",
+	}
+}
+
+// topRequesters lists who added the most of the played songs, most first
+func topRequesters(history []spotify.Song) string {
+	counts := make(map[string]int)
+	var requesters []string
+	for _, song := range history {
+		if song.AddedBy == "" {
+			continue
+		}
+		if counts[song.AddedBy] == 0 {
+			requesters = append(requesters, song.AddedBy)
+		}
+		counts[song.AddedBy]++
+	}
+
+	// Ties keep the order requesters first had a song played
+	sort.SliceStable(requesters, func(a, b int) bool {
+		return counts[requesters[a]] > counts[requesters[b]]
+	})
+	if len(requesters) > maxSummaryRequesters {
+		requesters = requesters[:maxSummaryRequesters]
+	}
+
+	lines := make([]string, len(requesters))
+	for i, id := range requesters {
+		lines[i] = fmt.Sprintf("%d. <@%s> · %d song(s)", i+1, id, counts[id])
+	}
+	return strings.Join(lines, "\n")
+}
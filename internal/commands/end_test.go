@@ -0,0 +1,125 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"jam-bot/internal/commands"
+	"jam-bot/internal/commands/commandstest"
+	"jam-bot/internal/spotify"
+)
+
+func TestEndCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		manageServer bool
+		noSession    bool
+		noHost       bool // The session was started before hosts were recorded
+		wantReply    string
+		wantEnded    bool
+	}{
+		{
+			name:      "no session",
+			userID:    hostID,
+			noSession: true,
+			wantReply: "no jam session in this channel",
+		},
+		{
+			name:      "host",
+			userID:    hostID,
+			wantEnded: true,
+		},
+		{
+			name:      "guest",
+			userID:    guestID,
+			wantReply: "Only the host, <@" + hostID + ">, or a server manager can end this jam session",
+		},
+		{
+			name:         "server manager",
+			userID:       "333",
+			manageServer: true,
+			wantEnded:    true,
+		},
+		{
+			name:      "participant of a session without a host",
+			userID:    guestID,
+			noHost:    true,
+			wantEnded: true,
+		},
+		{
+			name:      "outsider of a session without a host",
+			userID:    "333",
+			noHost:    true,
+			wantReply: "Only someone in this jam session or a server manager can end it",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newTestEnv(t)
+			switch {
+			case tt.noHost:
+				session := &spotify.Session{ChannelID: "channel", GuildID: "guild", Participants: []string{hostID, guestID}}
+				err := env.store.CreateSession(ctx, session)
+				if err != nil {
+					t.Fatalf("CreateSession: %v", err)
+				}
+				t.Cleanup(func() { env.svc.DeleteSession(ctx, "channel") })
+			case !tt.noSession:
+				env.startSession(t, testSongs...)
+			}
+
+			cmdCtx := commandstest.NewContext(tt.userID, "channel")
+			cmdCtx.Guild = "guild"
+			cmdCtx.ManageServer = tt.manageServer
+			err := commands.NewEndCommand(env.svc, commands.NewPanel(nil, commands.NewRegistry())).Execute(cmdCtx)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+
+			if !strings.Contains(cmdCtx.LastReply(), tt.wantReply) {
+				t.Errorf("reply = %q, want it to contain %q", cmdCtx.LastReply(), tt.wantReply)
+			}
+			if tt.noSession {
+				return
+			}
+
+			_, err = env.svc.LoadSession(ctx, "channel")
+			if ended := errors.Is(err, spotify.ErrSessionNotFound); ended != tt.wantEnded {
+				t.Errorf("session ended: %v, want %v (LoadSession: %v)", ended, tt.wantEnded, err)
+			}
+			if tt.wantEnded && (len(cmdCtx.Embeds) != 1 || cmdCtx.Embeds[0].Title != "🏁 Jam Session Ended") {
+				t.Errorf("embeds = %+v, want the session summary", cmdCtx.Embeds)
+			}
+		})
+	}
+}
+
+func TestEndCommandPausesPlayback(t *testing.T) {
+	env := newTestEnv(t)
+	env.startSession(t, testSongs...)
+
+	_, err := env.svc.StartPlayback(context.Background(), "channel")
+	if err != nil {
+		t.Fatalf("StartPlayback: %v", err)
+	}
+
+	ctx := commandstest.NewContext(hostID, "channel")
+	err = commands.NewEndCommand(env.svc, commands.NewPanel(nil, commands.NewRegistry())).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if len(ctx.Embeds) != 1 || !strings.Contains(ctx.Embeds[0].Description, "paused playback") {
+		t.Errorf("embeds = %+v, want a summary saying playback was paused", ctx.Embeds)
+	}
+	for _, user := range []string{"host", "guest"} {
+		if player := env.srv.Player(user); player.IsPlaying {
+			t.Errorf("%s's player is still playing", user)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// minSnowflakeLength tells Discord user IDs apart from weights when both are given as plain numbers
+const minSnowflakeLength = 15
+
+type FairCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewFairCommand(spotifyService *spotify.Service) *FairCommand {
+	return &FairCommand{spotifyService: spotifyService}
+}
+
+func (c *FairCommand) Name() string {
+	return "fair"
+}
+
+func (c *FairCommand) Description() string {
+	return "Takes turns between requesters so nobody dominates the queue. Usage: !fair [on | off] or !fair weight @user [1-5]"
+}
+
+func (c *FairCommand) Options() []*discordgo.ApplicationCommandOption {
+	minWeight, maxWeight := 1.0, 5.0
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "mode",
+			Description: "Turn the fair queue on or off; toggles when left out",
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "on", Value: "on"},
+				{Name: "off", Value: "off"},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionUser,
+			Name:        "user",
+			Description: "Requester whose songs per round to set",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "weight",
+			Description: "Songs the requester gets per round",
+			MinValue:    &minWeight,
+			MaxValue:    maxWeight,
+		},
+	}
+}
+
+func (c *FairCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	// Work out the requested mode, or the requester and weight to set
+	var mode, userID string
+	weight := 0
+	for _, arg := range args {
+		lower := strings.ToLower(arg)
+		if lower == "on" || lower == "off" {
+			mode = lower
+			continue
+		}
+		if lower == "weight" {
+			continue
+		}
+		if match := userMentionPattern.FindStringSubmatch(arg); match != nil {
+			userID = match[1]
+			continue
+		}
+
+		n, err := strconv.Atoi(arg)
+		switch {
+		case err == nil && len(arg) >= minSnowflakeLength:
+			userID = arg
+		case err == nil && n > 0:
+			weight = n
+		default:
+			return c.usage(ctx)
+		}
+	}
+
+	if userID != "" || weight != 0 {
+		if userID == "" || weight == 0 {
+			return c.usage(ctx)
+		}
+		return c.setWeight(ctx, channelID, userID, weight)
+	}
+
+	// Retrieve the current session
+	session, err := c.spotifyService.LoadSession(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	enabled := !session.Fair
+	if mode != "" {
+		enabled = mode == "on"
+	}
+
+	_, err = c.spotifyService.SetFairQueue(ctx.Context(), channelID, enabled)
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to change the fair queue: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to set fair queue: %w", err)
+	}
+
+	message := "➡️ Fair queue is off. Upcoming songs play in the order they were added."
+	if enabled {
+		message = "⚖️ Fair queue is on. Requesters now take turns; see the order with `!queue`."
+	}
+
+	err = ctx.Reply(message)
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
+
+// setWeight sets how many songs a requester gets per round
+func (c *FairCommand) setWeight(ctx Context, channelID, userID string, weight int) error {
+	err := c.spotifyService.SetFairWeight(ctx.Context(), channelID, userID, weight)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if errors.Is(err, spotify.ErrInvalidFairWeight) {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Invalid weight: %v.", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return nil
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to set the weight: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to set fair weight: %w", err)
+	}
+
+	err = ctx.Reply(fmt.Sprintf("⚖️ <@%s> now gets **%d** song(s) per round of the fair queue.", userID, weight))
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
+
+// usage explains the command's arguments
+func (c *FairCommand) usage(ctx Context) error {
+	err := ctx.Reply("❌ Usage: `!fair [on | off]` or `!fair weight @user [1-5]`")
+	if err != nil {
+		return fmt.Errorf("failed to send usage message: %w", err)
+	}
+	return nil
+}
@@ -27,15 +27,20 @@ func (c *HelpCommand) Description() string {
 	return "Lists all available commands"
 }
 
-// Execute runs the command with the given session and message
-func (c *HelpCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+// Options returns the command arguments
+func (c *HelpCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+// Execute runs the command for the given invocation
+func (c *HelpCommand) Execute(ctx Context) error {
 	var builder strings.Builder
 	builder.WriteString("Available commands:\n")
 	for _, cmd := range c.registry.commands {
 		builder.WriteString(fmt.Sprintf("`!%s` - %s\n", cmd.Name(), cmd.Description()))
 	}
 
-	_, err := s.ChannelMessageSend(m.ChannelID, builder.String())
+	err := ctx.Reply(builder.String())
 	if err != nil {
 		return fmt.Errorf("[ERROR] failed to send message: %w", err)
 	}
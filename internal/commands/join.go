@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"jam-bot/internal/spotify"
 
@@ -24,13 +24,16 @@ func (c *JoinCommand) Description() string {
 	return "Connects you to the current jam session."
 }
 
-func (c *JoinCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	channelID := m.ChannelID
-	userID := m.Author.ID
+func (c *JoinCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *JoinCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+	userID := ctx.Author().ID
 
 	if channelID == "" {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ This command can only be used within a channel.")
+		err := ctx.Reply("❌ This command can only be used within a channel.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -38,19 +41,14 @@ func (c *JoinCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 	}
 
 	// Check if the user is authenticated
-	isAuth, err := c.spotifyService.IsAuthenticated(ctx, userID)
+	isAuth, err := c.spotifyService.IsAuthenticated(ctx.Context(), userID)
 	if err != nil {
 		return fmt.Errorf("failed to check authentication status: %w", err)
 	}
 
 	if !isAuth {
 		// Inform the user to authenticate first
-		dm, err := s.UserChannelCreate(userID)
-		if err != nil {
-			return fmt.Errorf("failed to create DM channel: %w", err)
-		}
-
-		_, err = s.ChannelMessageSend(dm.ID, "You need to authenticate with Spotify first. Use `!auth` to authenticate.")
+		err = ctx.DM("You need to authenticate with Spotify first. Use `!auth` to authenticate.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -59,11 +57,14 @@ func (c *JoinCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 	}
 
 	// Add the user to the session
-	err = c.spotifyService.AddUserToSession(ctx, channelID, userID)
+	err = c.spotifyService.AddUserToSession(ctx.Context(), ctx.GuildID(), channelID, userID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
 	if err != nil {
 		// If user is already in session, inform them
-		if err.Error() == "user is already in the session" {
-			_, err = s.ChannelMessageSend(m.ChannelID, "You are already part of the jam session!")
+		if errors.Is(err, spotify.ErrAlreadyInSession) {
+			err = ctx.Reply("You are already part of the jam session!")
 			if err != nil {
 				return fmt.Errorf("failed to send message: %w", err)
 			}
@@ -73,7 +74,7 @@ func (c *JoinCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 	}
 
 	// Confirm to the user via message in the channel
-	_, err = s.ChannelMessageSend(m.ChannelID, "✅ You have joined the jam session!")
+	err = ctx.Reply("✅ You have joined the jam session!")
 	if err != nil {
 		return fmt.Errorf("failed to send confirmation message: %w", err)
 	}
@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"jam-bot/internal/spotify"
 
@@ -24,19 +24,25 @@ func (c *LeaveCommand) Description() string {
 	return "Removes you from the current jam session."
 }
 
-func (c *LeaveCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	channelID := m.ChannelID
-	userID := m.Author.ID
+func (c *LeaveCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *LeaveCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+	userID := ctx.Author().ID
 
 	// Remove the user from the session
-	err := c.spotifyService.RemoveUserFromSession(ctx, channelID, userID)
+	err := c.spotifyService.RemoveUserFromSession(ctx.Context(), channelID, userID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to remove user from session: %w", err)
 	}
 
 	// Confirm to the user via message in the channel
-	_, err = s.ChannelMessageSend(m.ChannelID, "✅ You have left the jam session.")
+	err = ctx.Reply("✅ You have left the jam session.")
 	if err != nil {
 		return fmt.Errorf("failed to send confirmation message: %w", err)
 	}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type MoveCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewMoveCommand(spotifyService *spotify.Service) *MoveCommand {
+	return &MoveCommand{spotifyService: spotifyService}
+}
+
+func (c *MoveCommand) Name() string {
+	return "move"
+}
+
+func (c *MoveCommand) Description() string {
+	return "Moves a song to another position in the queue."
+}
+
+func (c *MoveCommand) Options() []*discordgo.ApplicationCommandOption {
+	minPosition := 1.0
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "from",
+			Description: "Position of the song to move",
+			Required:    true,
+			MinValue:    &minPosition,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "to",
+			Description: "Position to move it to",
+			Required:    true,
+			MinValue:    &minPosition,
+		},
+	}
+}
+
+func (c *MoveCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	if len(args) != 2 {
+		err := ctx.Reply("❌ Please provide two positions. Usage: `!move [from] [to]`")
+		if err != nil {
+			return fmt.Errorf("failed to send usage message: %w", err)
+		}
+		return nil
+	}
+
+	from, okFrom := parsePosition(args[0])
+	to, okTo := parsePosition(args[1])
+	if !okFrom || !okTo {
+		err := ctx.Reply("❌ Invalid position. Please provide positive integers.")
+		if err != nil {
+			return fmt.Errorf("failed to send error message: %w", err)
+		}
+		return nil
+	}
+
+	// Move the song within the queue
+	song, err := c.spotifyService.MoveSong(ctx.Context(), channelID, from, to)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		sendErr := ctx.Reply(queueEditError("move the song", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to move song: %w", err)
+	}
+
+	// Confirm to the user
+	err = ctx.Reply(fmt.Sprintf("✅ Moved **%s** to position **%d**.", song.Title, to+1) + manualOrderNote)
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type NowPlayingCommand struct {
+	spotifyService *spotify.Service
+	panel          *Panel
+}
+
+func NewNowPlayingCommand(spotifyService *spotify.Service, panel *Panel) *NowPlayingCommand {
+	return &NowPlayingCommand{spotifyService: spotifyService, panel: panel}
+}
+
+func (c *NowPlayingCommand) Name() string {
+	return "nowplaying"
+}
+
+func (c *NowPlayingCommand) Description() string {
+	return "Shows the now playing panel with playback controls."
+}
+
+func (c *NowPlayingCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *NowPlayingCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	// Retrieve the current session
+	session, err := c.spotifyService.LoadSession(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	// Move the panel to the bottom of the channel
+	err = c.panel.Repost(session)
+	if err != nil {
+		return fmt.Errorf("failed to post now playing panel: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// panelButtonPrefix marks the custom IDs of the now playing panel buttons
+const panelButtonPrefix = "panel:"
+
+// progressBarLength is the number of segments in the panel progress bar
+const progressBarLength = 20
+
+// Panel keeps a "now playing" message with playback buttons in each session channel.
+// Buttons run the matching registered command, so they go through the same checks as typed commands.
+type Panel struct {
+	mu       sync.Mutex
+	session  *discordgo.Session
+	messages map[string]string          // channel ID -> panel message ID
+	rendered map[string]renderedSession // channel ID -> the session state its panel shows
+	ended    map[string]int64           // channel ID -> creation time of the session that last ended there
+	registry *Registry
+}
+
+// renderedSession identifies the session state a panel was last rendered from
+type renderedSession struct {
+	createdAtMs int64
+	version     int64
+}
+
+// NewPanel creates a now playing panel that dispatches button clicks to the registry's commands
+func NewPanel(s *discordgo.Session, registry *Registry) *Panel {
+	return &Panel{
+		session:  s,
+		messages: make(map[string]string),
+		rendered: make(map[string]renderedSession),
+		ended:    make(map[string]int64),
+		registry: registry,
+	}
+}
+
+// Update renders the session's playback state into its channel's panel, posting the panel if there is none yet.
+// Updates arrive from separate goroutines, so ones older than what the panel shows are dropped, as are
+// updates for a session that has already ended, so a late one can't bring its panel back.
+func (p *Panel) Update(session *spotify.Session) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if createdAt, ok := p.ended[session.ChannelID]; ok && createdAt == session.CreatedAtMs {
+		return nil
+	}
+	last, ok := p.rendered[session.ChannelID]
+	if ok && last.createdAtMs == session.CreatedAtMs && session.Version <= last.version {
+		return nil
+	}
+	rendered := renderedSession{createdAtMs: session.CreatedAtMs, version: session.Version}
+
+	embed := panelEmbed(session)
+	components := panelComponents(session)
+
+	if messageID, ok := p.messages[session.ChannelID]; ok {
+		_, err := p.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         messageID,
+			Channel:    session.ChannelID,
+			Embeds:     &[]*discordgo.MessageEmbed{embed},
+			Components: &components,
+		})
+		if err == nil {
+			p.rendered[session.ChannelID] = rendered
+			return nil
+		}
+		// The panel was probably deleted; fall through and post a fresh one
+		delete(p.messages, session.ChannelID)
+	}
+
+	msg, err := p.session.ChannelMessageSendComplex(session.ChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: components,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send now playing panel: %w", err)
+	}
+
+	p.messages[session.ChannelID] = msg.ID
+	p.rendered[session.ChannelID] = rendered
+	return nil
+}
+
+// Repost replaces a channel's panel with a new one at the bottom of the channel
+func (p *Panel) Repost(session *spotify.Session) error {
+	p.mu.Lock()
+	if messageID, ok := p.messages[session.ChannelID]; ok {
+		// Best effort; the old panel may already be gone
+		_ = p.session.ChannelMessageDelete(session.ChannelID, messageID)
+		delete(p.messages, session.ChannelID)
+	}
+	// The new panel shows the session as it is now, even if the old one already did
+	delete(p.rendered, session.ChannelID)
+	p.mu.Unlock()
+
+	return p.Update(session)
+}
+
+// Remove deletes the panel of a session that has ended
+func (p *Panel) Remove(session *spotify.Session) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	channelID := session.ChannelID
+	p.ended[channelID] = session.CreatedAtMs
+	delete(p.rendered, channelID)
+
+	if messageID, ok := p.messages[channelID]; ok {
+		// Best effort; the panel may already be gone
+		_ = p.session.ChannelMessageDelete(channelID, messageID)
+		delete(p.messages, channelID)
+	}
+}
+
+// HandlesComponent reports whether a message component custom ID belongs to the panel
+func (p *Panel) HandlesComponent(customID string) bool {
+	return strings.HasPrefix(customID, panelButtonPrefix)
+}
+
+// HandleComponent runs the command behind a clicked panel button
+func (p *Panel) HandleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	data := i.MessageComponentData()
+	if !p.HandlesComponent(data.CustomID) {
+		return errors.New("not a panel button")
+	}
+
+	cmd, err := p.registry.Get(strings.TrimPrefix(data.CustomID, panelButtonPrefix))
+	if err != nil {
+		return err
+	}
+
+	return p.registry.executeDeferred(s, i, cmd, nil)
+}
+
+// panelEmbed renders the playback state of a session
+func panelEmbed(session *spotify.Session) *discordgo.MessageEmbed {
+	playback := session.Playback
+	song := playback.CurrentSong
+
+	if song.URI == "" {
+		return &discordgo.MessageEmbed{
+			Title:       "🎶 Now Playing",
+			Description: "Nothing is playing. Add songs with `!add` and start with `!play`.",
+		}
+	}
+
+	status := "⏸️ Paused"
+	if playback.IsPlaying {
+		status = "▶️ Playing"
+	}
+	if modes := playModes(session); modes != "" {
+		status += " · " + modes
+	}
+
+	position := playback.PositionAt(time.Now())
+	progress := formatDuration(position)
+	if song.DurationMs > 0 {
+		progress = fmt.Sprintf("%s %s / %s", progressBar(position, song.DurationMs), formatDuration(position), formatDuration(song.DurationMs))
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Progress", Value: progress},
+	}
+	if song.AddedBy != "" {
+		addedBy := "<@" + song.AddedBy + ">"
+		if addedAt := song.AddedAt(); !addedAt.IsZero() {
+			addedBy += fmt.Sprintf(" <t:%d:R>", addedAt.Unix())
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Added by", Value: addedBy, Inline: true})
+	}
+	if song.Popularity > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Popularity", Value: fmt.Sprintf("%d/100", song.Popularity), Inline: true})
+	}
+	if len(session.Queue) > 1 {
+		next := session.Queue[1]
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Up next", Value: fmt.Sprintf("%s - %s", next.Title, next.Artist), Inline: true})
+	}
+
+	description := fmt.Sprintf("**%s**\nby **%s**", song.Title, song.Artist)
+	if song.Explicit {
+		description = fmt.Sprintf("**%s** 🅴\nby **%s**", song.Title, song.Artist)
+	}
+	if song.Album != "" {
+		description += fmt.Sprintf("\non *%s*", song.Album)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🎶 Now Playing",
+		URL:         song.ExternalURL,
+		Description: description,
+		Fields:      fields,
+		Footer:      &discordgo.MessageEmbedFooter{Text: status},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	if song.ArtworkURL != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: song.ArtworkURL}
+	}
+	return embed
+}
+
+// panelComponents builds the play/pause, skip and leave buttons for a session
+func panelComponents(session *spotify.Session) []discordgo.MessageComponent {
+	playPause := discordgo.Button{
+		Label:    "Play",
+		Emoji:    &discordgo.ComponentEmoji{Name: "▶️"},
+		Style:    discordgo.SuccessButton,
+		CustomID: panelButtonPrefix + "play",
+	}
+	if session.Playback.IsPlaying {
+		playPause = discordgo.Button{
+			Label:    "Pause",
+			Emoji:    &discordgo.ComponentEmoji{Name: "⏸️"},
+			Style:    discordgo.SecondaryButton,
+			CustomID: panelButtonPrefix + "pause",
+		}
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				playPause,
+				discordgo.Button{
+					Label:    "Skip",
+					Emoji:    &discordgo.ComponentEmoji{Name: "⏭️"},
+					Style:    discordgo.PrimaryButton,
+					CustomID: panelButtonPrefix + "skip",
+				},
+				discordgo.Button{
+					Label:    "Leave",
+					Emoji:    &discordgo.ComponentEmoji{Name: "👋"},
+					Style:    discordgo.DangerButton,
+					CustomID: panelButtonPrefix + "leave",
+				},
+			},
+		},
+	}
+}
+
+// playModes describes a session's shuffle, repeat and fair queue settings, or returns "" if all are off
+func playModes(session *spotify.Session) string {
+	var modes []string
+	if session.Shuffle {
+		modes = append(modes, fmt.Sprintf("🔀 Shuffle (seed %d)", session.ShuffleSeed))
+	}
+	switch session.RepeatMode() {
+	case spotify.RepeatTrack:
+		modes = append(modes, "🔂 Repeat track")
+	case spotify.RepeatQueue:
+		modes = append(modes, "🔁 Repeat queue")
+	}
+	if session.Fair {
+		modes = append(modes, "⚖️ Fair queue")
+	}
+	return strings.Join(modes, " · ")
+}
+
+// progressBar draws the position within a song as a text bar
+func progressBar(positionMs, durationMs int) string {
+	filled := positionMs * progressBarLength / durationMs
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > progressBarLength {
+		filled = progressBarLength
+	}
+	return strings.Repeat("▬", filled) + "🔘" + strings.Repeat("▬", progressBarLength-filled)
+}
+
+// formatDuration formats milliseconds as m:ss, or h:mm:ss from an hour up
+func formatDuration(ms int) string {
+	if ms < 0 {
+		ms = 0
+	}
+	seconds := ms / 1000
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
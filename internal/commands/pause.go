@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"jam-bot/internal/spotify"
 
@@ -24,12 +24,15 @@ func (c *PauseCommand) Description() string {
 	return "Pauses the current playback."
 }
 
-func (c *PauseCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	channelID := m.ChannelID
+func (c *PauseCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *PauseCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
 
 	if channelID == "" {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ This command can only be used within a channel.")
+		err := ctx.Reply("❌ This command can only be used within a channel.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -37,9 +40,12 @@ func (c *PauseCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 	}
 
 	// Pause playback
-	err := c.spotifyService.PausePlayback(ctx, channelID)
+	report, err := c.spotifyService.PausePlayback(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
 	if err != nil {
-		_, sendErr := s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("❌ Failed to pause playback: %v", err))
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to pause playback: %v", err))
 		if sendErr != nil {
 			return fmt.Errorf("failed to send error message: %w", sendErr)
 		}
@@ -47,7 +53,7 @@ func (c *PauseCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 	}
 
 	// Confirm to the user
-	_, err = s.ChannelMessageSend(m.ChannelID, "✅ Playback has been paused.")
+	err = ctx.Reply("✅ Playback has been paused." + reportSummary(report))
 	if err != nil {
 		return fmt.Errorf("failed to send confirmation message: %w", err)
 	}
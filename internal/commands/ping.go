@@ -19,9 +19,14 @@ func (c *PingCommand) Description() string {
 	return "Responds with 'Pong!'"
 }
 
-// Execute runs the command with the given session and message
-func (c *PingCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	_, err := s.ChannelMessageSend(m.ChannelID, "Pong!")
+// Options returns the command arguments
+func (c *PingCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+// Execute runs the command for the given invocation
+func (c *PingCommand) Execute(ctx Context) error {
+	err := ctx.Reply("Pong!")
 	if err != nil {
 		return errors.New("[ERROR] failed to send message:" + err.Error())
 	}
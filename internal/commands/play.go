@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"jam-bot/internal/spotify"
 
@@ -24,12 +24,15 @@ func (c *PlayCommand) Description() string {
 	return "Starts playback of the queued songs."
 }
 
-func (c *PlayCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	channelID := m.ChannelID
+func (c *PlayCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *PlayCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
 
 	if channelID == "" {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ This command can only be used within a channel.")
+		err := ctx.Reply("❌ This command can only be used within a channel.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -37,9 +40,12 @@ func (c *PlayCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 	}
 
 	// Start playback
-	err := c.spotifyService.StartPlayback(ctx, channelID)
+	report, err := c.spotifyService.StartPlayback(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
 	if err != nil {
-		_, sendErr := s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("❌ Failed to start playback: %v", err))
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to start playback: %v", err))
 		if sendErr != nil {
 			return fmt.Errorf("failed to send error message: %w", sendErr)
 		}
@@ -47,7 +53,7 @@ func (c *PlayCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 	}
 
 	// Confirm to the user
-	_, err = s.ChannelMessageSend(m.ChannelID, "✅ Playback has started.")
+	err = ctx.Reply("✅ Playback has started." + reportSummary(report))
 	if err != nil {
 		return fmt.Errorf("failed to send confirmation message: %w", err)
 	}
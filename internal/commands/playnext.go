@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type PlayNextCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewPlayNextCommand(spotifyService *spotify.Service) *PlayNextCommand {
+	return &PlayNextCommand{spotifyService: spotifyService}
+}
+
+func (c *PlayNextCommand) Name() string {
+	return "playnext"
+}
+
+func (c *PlayNextCommand) Description() string {
+	return "Adds a song to play right after the current one."
+}
+
+func (c *PlayNextCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "song",
+			Description: "The song to search for",
+			Required:    true,
+		},
+	}
+}
+
+func (c *PlayNextCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	if len(args) == 0 {
+		err := ctx.Reply("❌ Please provide a song name. Usage: `!playnext [song name]`")
+		if err != nil {
+			return fmt.Errorf("failed to send usage message: %w", err)
+		}
+		return nil
+	}
+
+	songName := strings.Join(args, " ")
+
+	// Songs can only be queued into a running session, so don't go to Spotify without one
+	_, err := c.spotifyService.LoadSession(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	// Search for the song using Spotify API
+	song, err := c.spotifyService.SearchSong(ctx.Context(), ctx.Author().ID, songName)
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to find the song: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to search song: %w", err)
+	}
+
+	song.AddedBy = ctx.Author().ID
+
+	// Put the song at the front of the upcoming songs
+	index, err := c.spotifyService.PlayNext(ctx.Context(), channelID, song)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if message, ok := policyRejection(err); ok {
+		err = ctx.Reply(message)
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to add the song to the queue: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to add song to queue: %w", err)
+	}
+
+	// Confirm to the user
+	err = ctx.Reply(fmt.Sprintf("⏭️ **%s** by **%s** will play next (position **%d**).", song.Title, song.Artist, index+1) + manualOrderNote)
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// policyUsage lists the rules `!policy` can change and the values they take
+const policyUsage = "`!policy queue [n | off]`, `!policy peruser [n | off]`, `!policy length [m:ss | off]`, " +
+	"`!policy explicit [allow | block]`, `!policy duplicates [allow | block]` or `!policy recent [n | off]`"
+
+type PolicyCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewPolicyCommand(spotifyService *spotify.Service) *PolicyCommand {
+	return &PolicyCommand{spotifyService: spotifyService}
+}
+
+func (c *PolicyCommand) Name() string {
+	return "policy"
+}
+
+func (c *PolicyCommand) Description() string {
+	return "Shows or changes the server's queue limits and content rules. Usage: !policy [rule value]"
+}
+
+func (c *PolicyCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "rule",
+			Description: "Rule to change; shows the current rules when left out",
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "queue length", Value: "queue"},
+				{Name: "songs per person", Value: "peruser"},
+				{Name: "song length", Value: "length"},
+				{Name: "explicit songs", Value: "explicit"},
+				{Name: "duplicates", Value: "duplicates"},
+				{Name: "recent repeats", Value: "recent"},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "value",
+			Description: "A number, a length like 7:00, allow, block or off",
+		},
+	}
+}
+
+func (c *PolicyCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	guildID := ctx.GuildID()
+
+	if guildID == "" {
+		err := ctx.Reply("❌ Queue policies can only be used within a server.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	policy, err := c.spotifyService.QueuePolicy(ctx.Context(), guildID)
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to load the queue policy: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to load queue policy: %w", err)
+	}
+
+	if len(args) == 0 {
+		err = ctx.ReplyEmbed(policyEmbed(policy))
+		if err != nil {
+			return fmt.Errorf("failed to send queue policy: %w", err)
+		}
+		return nil
+	}
+
+	if len(args) != 2 {
+		return c.usage(ctx)
+	}
+
+	if !ctx.CanManageServer() {
+		err = ctx.Reply("❌ Only members who can manage the server can change the queue policy.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	rule, err := applyPolicyRule(&policy, strings.ToLower(args[0]), strings.ToLower(args[1]))
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ %v. Usage: %s", err, policyUsage))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return nil
+	}
+
+	err = c.spotifyService.SetQueuePolicy(ctx.Context(), guildID, policy)
+	if errors.Is(err, spotify.ErrInvalidPolicy) {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ %v.", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return nil
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to change the queue policy: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to set queue policy: %w", err)
+	}
+
+	err = ctx.Reply(fmt.Sprintf("✅ **%s** is now: %s. It applies to songs added from now on.", rule, policyRuleValue(policy, rule)))
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
+
+// usage explains the command's arguments
+func (c *PolicyCommand) usage(ctx Context) error {
+	err := ctx.Reply("❌ Usage: `!policy` to see the rules, or " + policyUsage)
+	if err != nil {
+		return fmt.Errorf("failed to send usage message: %w", err)
+	}
+	return nil
+}
+
+// applyPolicyRule sets the rule named by key to value, returning the rule that changed
+func applyPolicyRule(policy *spotify.QueuePolicy, key, value string) (spotify.PolicyRule, error) {
+	switch key {
+	case "queue":
+		n, err := parseLimit(value)
+		policy.MaxQueueLength = n
+		return spotify.RuleQueueLength, err
+	case "peruser":
+		n, err := parseLimit(value)
+		policy.MaxPerUser = n
+		return spotify.RulePerUser, err
+	case "recent":
+		n, err := parseLimit(value)
+		policy.RecentLimit = n
+		return spotify.RuleRecentlyPlayed, err
+	case "length":
+		if value == "off" {
+			policy.MaxDurationMs = 0
+			return spotify.RuleSongLength, nil
+		}
+		ms, relative, err := parseSeekPosition(value)
+		if err != nil || relative || ms == 0 {
+			return spotify.RuleSongLength, fmt.Errorf("invalid song length %q", value)
+		}
+		policy.MaxDurationMs = ms
+		return spotify.RuleSongLength, nil
+	case "explicit":
+		block, err := parseBlock(value)
+		policy.NoExplicit = block
+		return spotify.RuleExplicit, err
+	case "duplicates":
+		block, err := parseBlock(value)
+		policy.NoDuplicates = block
+		return spotify.RuleDuplicates, err
+	default:
+		return "", fmt.Errorf("unknown rule %q", key)
+	}
+}
+
+// parseLimit parses a positive count, or "off" for no limit
+func parseLimit(value string) (int, error) {
+	if value == "off" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid limit %q", value)
+	}
+	return n, nil
+}
+
+// parseBlock parses "block" or "allow"
+func parseBlock(value string) (bool, error) {
+	switch value {
+	case "block":
+		return true, nil
+	case "allow":
+		return false, nil
+	default:
+		return false, fmt.Errorf("expected allow or block, not %q", value)
+	}
+}
+
+// policyRuleValue describes how a policy sets one rule
+func policyRuleValue(policy spotify.QueuePolicy, rule spotify.PolicyRule) string {
+	limit := func(n int, unit string) string {
+		if n == 0 {
+			return "off"
+		}
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	blocked := func(block bool) string {
+		if block {
+			return "blocked"
+		}
+		return "allowed"
+	}
+
+	switch rule {
+	case spotify.RuleQueueLength:
+		return limit(policy.MaxQueueLength, "songs")
+	case spotify.RulePerUser:
+		return limit(policy.MaxPerUser, "songs each")
+	case spotify.RuleSongLength:
+		if policy.MaxDurationMs == 0 {
+			return "off"
+		}
+		return "up to " + formatDuration(policy.MaxDurationMs)
+	case spotify.RuleExplicit:
+		return blocked(policy.NoExplicit)
+	case spotify.RuleDuplicates:
+		return blocked(policy.NoDuplicates)
+	case spotify.RuleRecentlyPlayed:
+		return limit(policy.RecentLimit, "songs back")
+	default:
+		return ""
+	}
+}
+
+// policyEmbed lists the rules of a guild's queue policy
+func policyEmbed(policy spotify.QueuePolicy) Embed {
+	rules := []spotify.PolicyRule{
+		spotify.RuleQueueLength,
+		spotify.RulePerUser,
+		spotify.RuleSongLength,
+		spotify.RuleExplicit,
+		spotify.RuleDuplicates,
+		spotify.RuleRecentlyPlayed,
+	}
+
+	fields := make([]EmbedField, len(rules))
+	for i, rule := range rules {
+		fields[i] = EmbedField{Name: string(rule), Value: policyRuleValue(policy, rule), Inline: true}
+	}
+
+	return Embed{
+		Title:       "📋 Queue Policy",
+		Description: "Songs that break a rule are turned away when they are added.",
+		Fields:      fields,
+		Footer:      "Members who can manage the server can change the rules with !policy [rule value]",
+	}
+}
+
+// policyRejection returns the message telling the user which rule kept their song out, if err is a policy rejection
+func policyRejection(err error) (string, bool) {
+	var blocked *spotify.PolicyError
+	if !errors.As(err, &blocked) {
+		return "", false
+	}
+	return fmt.Sprintf("🚫 **%s** wasn't added: it's %v.", blocked.Song.Title, blocked), true
+}
+
+// skippedByRule summarizes the songs of a link that rules kept out, counted by rule
+func skippedByRule(rejected []*spotify.PolicyError) string {
+	var rules []spotify.PolicyRule
+	counts := make(map[spotify.PolicyRule]int)
+	for _, blocked := range rejected {
+		if counts[blocked.Rule] == 0 {
+			rules = append(rules, blocked.Rule)
+		}
+		counts[blocked.Rule]++
+	}
+
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		parts[i] = fmt.Sprintf("%d by the **%s** rule", counts[rule], rule)
+	}
+	return fmt.Sprintf("🚫 Skipped %d songs: %s.", len(rejected), strings.Join(parts, ", "))
+}
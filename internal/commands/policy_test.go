@@ -0,0 +1,106 @@
+package commands_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"jam-bot/internal/commands"
+	"jam-bot/internal/commands/commandstest"
+	"jam-bot/internal/spotify"
+)
+
+func TestPolicyCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		guild        string
+		args         []string
+		manageServer bool
+		wantReply    string
+		wantEmbed    string
+		wantPolicy   spotify.QueuePolicy
+	}{
+		{
+			name:      "outside a server",
+			args:      []string{"queue", "5"},
+			wantReply: "Queue policies can only be used within a server",
+		},
+		{
+			name:      "show",
+			guild:     "guild",
+			wantEmbed: "📋 Queue Policy",
+		},
+		{
+			name:      "change without permission",
+			guild:     "guild",
+			args:      []string{"queue", "5"},
+			wantReply: "Only members who can manage the server can change the queue policy",
+		},
+		{
+			name:         "change queue length",
+			guild:        "guild",
+			args:         []string{"queue", "5"},
+			manageServer: true,
+			wantReply:    "**queue length** is now: 5 songs",
+			wantPolicy:   spotify.QueuePolicy{MaxQueueLength: 5},
+		},
+		{
+			name:         "block explicit songs",
+			guild:        "guild",
+			args:         []string{"Explicit", "BLOCK"},
+			manageServer: true,
+			wantReply:    "**explicit songs** is now",
+			wantPolicy:   spotify.QueuePolicy{NoExplicit: true},
+		},
+		{
+			name:         "invalid limit",
+			guild:        "guild",
+			args:         []string{"queue", "-1"},
+			manageServer: true,
+			wantReply:    "invalid limit",
+		},
+		{
+			name:         "unknown rule",
+			guild:        "guild",
+			args:         []string{"volume", "5"},
+			manageServer: true,
+			wantReply:    "unknown rule",
+		},
+		{
+			name:         "missing value",
+			guild:        "guild",
+			args:         []string{"queue"},
+			manageServer: true,
+			wantReply:    "Usage:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+
+			ctx := commandstest.NewContext(guestID, "channel", tt.args...)
+			ctx.Guild = tt.guild
+			ctx.ManageServer = tt.manageServer
+			err := commands.NewPolicyCommand(env.svc).Execute(ctx)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+
+			if !strings.Contains(ctx.LastReply(), tt.wantReply) {
+				t.Errorf("reply = %q, want it to contain %q", ctx.LastReply(), tt.wantReply)
+			}
+			if tt.wantEmbed != "" && (len(ctx.Embeds) != 1 || ctx.Embeds[0].Title != tt.wantEmbed) {
+				t.Errorf("embeds = %+v, want %q", ctx.Embeds, tt.wantEmbed)
+			}
+
+			policy, err := env.svc.QueuePolicy(context.Background(), "guild")
+			if err != nil {
+				t.Fatalf("QueuePolicy: %v", err)
+			}
+			if policy != tt.wantPolicy {
+				t.Errorf("policy = %+v, want %+v", policy, tt.wantPolicy)
+			}
+		})
+	}
+}
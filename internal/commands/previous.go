@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type PreviousCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewPreviousCommand(spotifyService *spotify.Service) *PreviousCommand {
+	return &PreviousCommand{spotifyService: spotifyService}
+}
+
+func (c *PreviousCommand) Name() string {
+	return "previous"
+}
+
+func (c *PreviousCommand) Description() string {
+	return "Goes back to the previously played song for everyone in the jam session."
+}
+
+func (c *PreviousCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *PreviousCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	// Go back to the previous song
+	report, err := c.spotifyService.PreviousTrack(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to go back: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to go to previous song: %w", err)
+	}
+
+	// Confirm to the user
+	err = ctx.Reply(fmt.Sprintf("⏮️ Back to **%s** by **%s**.", report.Song.Title, report.Song.Artist) + reportSummary(report))
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
@@ -2,19 +2,47 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"jam-bot/internal/spotify"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// queueButtonPrefix marks the custom IDs of the queue page buttons
+const queueButtonPrefix = "queue:"
+
+// queuePageSize is the number of songs shown on each queue page
+const queuePageSize = 10
+
+// queueViewTimeout is how long after its last use a queue message keeps turning pages
+const queueViewTimeout = 15 * time.Minute
+
+// queueView is the page a queue message is showing
+type queueView struct {
+	channelID string
+	page      int // Zero-based
+	expiresAt time.Time
+}
+
+// QueueCommand shows the queue a page at a time. Each queue message remembers its own page,
+// so its previous and next buttons work independently of other queue messages.
 type QueueCommand struct {
 	spotifyService *spotify.Service
+
+	mu    sync.Mutex
+	views map[string]*queueView // view ID -> page shown by one queue message
 }
 
 func NewQueueCommand(spotifyService *spotify.Service) *QueueCommand {
-	return &QueueCommand{spotifyService: spotifyService}
+	return &QueueCommand{
+		spotifyService: spotifyService,
+		views:          make(map[string]*queueView),
+	}
 }
 
 func (c *QueueCommand) Name() string {
@@ -22,47 +50,295 @@ func (c *QueueCommand) Name() string {
 }
 
 func (c *QueueCommand) Description() string {
-	return "Displays the current song queue."
+	return "Displays the current song queue. Usage: !queue [page]"
 }
 
-func (c *QueueCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	channelID := m.ChannelID
+func (c *QueueCommand) Options() []*discordgo.ApplicationCommandOption {
+	minPage := 1.0
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "page",
+			Description: "The page of the queue to show",
+			Required:    false,
+			MinValue:    &minPage,
+		},
+	}
+}
+
+func (c *QueueCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
 
 	if channelID == "" {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ This command can only be used within a channel.")
+		err := ctx.Reply("❌ This command can only be used within a channel.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
 		return nil
 	}
 
+	page := 0
+	if len(args) > 0 {
+		number, err := strconv.Atoi(args[0])
+		if err != nil || number < 1 {
+			err := ctx.Reply("❌ Invalid page. Please provide a positive integer. Usage: `!queue [page]`")
+			if err != nil {
+				return fmt.Errorf("failed to send error message: %w", err)
+			}
+			return nil
+		}
+		page = number - 1
+	}
+
 	// Retrieve the current session
-	session, err := c.spotifyService.LoadSession(ctx, channelID)
+	session, err := c.spotifyService.LoadSession(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to load session: %w", err)
 	}
 
 	if len(session.Queue) == 0 {
-		_, err := s.ChannelMessageSend(m.ChannelID, "🎶 The queue is currently empty.")
+		err := ctx.Reply("🎶 The queue is currently empty.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
 		return nil
 	}
 
-	// Build the queue list
-	var queueList []string
-	for idx, song := range session.Queue {
-		queueList = append(queueList, fmt.Sprintf("%d. **%s** by **%s**", idx+1, song.Title, song.Artist))
+	embed, page, pages := queuePage(session, page)
+	if pages == 1 {
+		err = ctx.ReplyEmbed(embed)
+		if err != nil {
+			return fmt.Errorf("failed to send queue message: %w", err)
+		}
+		return nil
 	}
 
-	queueMessage := "🎶 **Current Queue:**\n" + strings.Join(queueList, "\n")
+	viewID := c.addView(&queueView{channelID: channelID, page: page})
 
-	_, err = s.ChannelMessageSend(m.ChannelID, queueMessage)
+	err = ctx.ReplyButtons(embed, queueButtons(viewID, page, pages))
 	if err != nil {
+		c.removeView(viewID)
 		return fmt.Errorf("failed to send queue message: %w", err)
 	}
 
 	return nil
 }
+
+// HandlesComponent reports whether a message component custom ID belongs to a queue message
+func (c *QueueCommand) HandlesComponent(customID string) bool {
+	return strings.HasPrefix(customID, queueButtonPrefix)
+}
+
+// HandleComponent turns the page of the queue message whose button was clicked
+func (c *QueueCommand) HandleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	data := i.MessageComponentData()
+	viewID, direction, ok := strings.Cut(strings.TrimPrefix(data.CustomID, queueButtonPrefix), ":")
+	if !c.HandlesComponent(data.CustomID) || !ok {
+		return errors.New("not a queue button")
+	}
+
+	c.mu.Lock()
+	view, ok := c.views[viewID]
+	ok = ok && time.Now().Before(view.expiresAt)
+	var channelID string
+	var page int
+	if ok {
+		channelID, page = view.channelID, view.page
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return respondEphemeral(s, i, "⌛ This queue message has expired. Run `!queue` again.")
+	}
+
+	switch direction {
+	case "prev":
+		page--
+	case "next":
+		page++
+	}
+
+	session, err := c.spotifyService.LoadSession(context.Background(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return respondEphemeral(s, i, noSessionMessage)
+	}
+	if err != nil {
+		return respondEphemeral(s, i, fmt.Sprintf("❌ Failed to load the queue: %v", err))
+	}
+
+	if len(session.Queue) == 0 {
+		c.removeView(viewID)
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    "🎶 The queue is currently empty.",
+				Embeds:     []*discordgo.MessageEmbed{},
+				Components: []discordgo.MessageComponent{},
+			},
+		})
+	}
+
+	// The queue may have shrunk since the message was sent, so the page is clamped again
+	embed, page, pages := queuePage(session, page)
+
+	c.mu.Lock()
+	if view, ok := c.views[viewID]; ok {
+		view.page = page
+		view.expiresAt = time.Now().Add(queueViewTimeout)
+	}
+	c.mu.Unlock()
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{toDiscordEmbed(embed)},
+			Components: toDiscordButtons(queueButtons(viewID, page, pages)),
+		},
+	})
+}
+
+// queuePage renders one page of the queue, clamping page to the pages that exist.
+// It returns the page shown and the number of pages.
+func queuePage(session *spotify.Session, page int) (Embed, int, int) {
+	pages := (len(session.Queue) + queuePageSize - 1) / queuePageSize
+	if page >= pages {
+		page = pages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	start := page * queuePageSize
+	end := start + queuePageSize
+	if end > len(session.Queue) {
+		end = len(session.Queue)
+	}
+
+	playing := session.Playback.CurrentSong.URI != "" && session.Queue[0].URI == session.Playback.CurrentSong.URI
+
+	var lines []string
+	for idx := start; idx < end; idx++ {
+		line := queueLine(idx+1, session.Queue[idx])
+		if idx == 0 && playing {
+			line = "▶️ " + line
+		}
+		lines = append(lines, line)
+	}
+
+	// Time left: the rest of the current song plus everything after it
+	remainingMs := 0
+	for idx, song := range session.Queue {
+		remainingMs += song.DurationMs
+		if idx == 0 && playing {
+			remainingMs -= session.Playback.PositionAt(time.Now())
+		}
+	}
+
+	footer := fmt.Sprintf("Page %d/%d · %d songs · %s remaining", page+1, pages, len(session.Queue), formatDuration(remainingMs))
+	if modes := playModes(session); modes != "" {
+		footer += " · " + modes
+	}
+
+	// Sent as an embed so the requester mentions don't notify anyone
+	return Embed{
+		Title:       "🎶 Current Queue",
+		Description: strings.Join(lines, "\n"),
+		Footer:      footer,
+	}, page, pages
+}
+
+// queueButtons builds the previous and next buttons of a queue message
+func queueButtons(viewID string, page, pages int) []Button {
+	return []Button{
+		{ID: queueButtonPrefix + viewID + ":prev", Label: "Previous", Emoji: "◀️", Disabled: page == 0},
+		{ID: queueButtonPrefix + viewID + ":next", Label: "Next", Emoji: "▶️", Disabled: page >= pages-1},
+	}
+}
+
+// addView remembers the page a new queue message shows, forgets expired ones, and returns the view's ID
+func (c *QueueCommand) addView(view *queueView) string {
+	viewID := strconv.FormatInt(time.Now().UnixNano(), 36)
+	view.expiresAt = time.Now().Add(queueViewTimeout)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, existing := range c.views {
+		if time.Now().After(existing.expiresAt) {
+			delete(c.views, id)
+		}
+	}
+	c.views[viewID] = view
+
+	return viewID
+}
+
+// removeView forgets a queue message's page
+func (c *QueueCommand) removeView(viewID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.views, viewID)
+}
+
+// respondEphemeral answers a component interaction with a message only the clicking user sees
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// queueLine renders a queued song with its length, explicit marker, requester and when it was added
+func queueLine(position int, song spotify.Song) string {
+	title := "**" + song.Title + "**"
+	if song.ExternalURL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, song.ExternalURL)
+	}
+
+	line := fmt.Sprintf("%d. %s by **%s**", position, title, song.Artist)
+	if song.Explicit {
+		line += " 🅴"
+	}
+	if song.DurationMs > 0 {
+		line += " `" + formatDuration(song.DurationMs) + "`"
+	}
+	if song.AddedBy != "" {
+		line += " · <@" + song.AddedBy + ">"
+	}
+	if addedAt := song.AddedAt(); !addedAt.IsZero() {
+		line += fmt.Sprintf(" <t:%d:R>", addedAt.Unix())
+	}
+	return line
+}
+
+// parsePosition parses a 1-based queue position as shown by `!queue` into a queue index
+func parsePosition(arg string) (int, bool) {
+	position, err := strconv.Atoi(arg)
+	if err != nil || position < 1 {
+		return 0, false
+	}
+	return position - 1, true
+}
+
+// manualOrderNote warns that a hand-placed song keeps its place only until the queue is re-sorted
+const manualOrderNote = "\nℹ️ Changing shuffle, the fair queue or fair weights re-sorts the queue and undoes this."
+
+// queueEditError turns a failed queue edit into a reply
+func queueEditError(action string, err error) string {
+	switch {
+	case errors.Is(err, spotify.ErrCurrentSongLocked):
+		return "❌ The song that is playing stays at the top of the queue. Use `!skip` to move on from it."
+	case errors.Is(err, spotify.ErrPositionOutOfRange):
+		return "❌ There is no song at that position. Use `!queue` to see the positions."
+	default:
+		return fmt.Sprintf("❌ Failed to %s: %v", action, err)
+	}
+}
@@ -1,12 +1,20 @@
 package commands
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// ErrCommandNotFound is returned when no command is registered under a name
+var ErrCommandNotFound = errors.New("command not found")
+
 // Registry holds all registered commands
 type Registry struct {
 	commands map[string]Command
@@ -28,7 +36,7 @@ func (r *Registry) Register(c Command) {
 func (r *Registry) Get(name string) (Command, error) {
 	cmd, exists := r.commands[strings.ToLower(name)]
 	if !exists {
-		return nil, errors.New("command not found")
+		return nil, ErrCommandNotFound
 	}
 	return cmd, nil
 }
@@ -42,7 +50,7 @@ func (r *Registry) ExecuteCommand(s *discordgo.Session, m *discordgo.MessageCrea
 	content := strings.TrimPrefix(m.Content, prefix)
 	args := strings.Fields(content)
 	if len(args) == 0 {
-		return errors.New("empty command")
+		return nil
 	}
 
 	cmdName := strings.ToLower(args[0])
@@ -53,7 +61,138 @@ func (r *Registry) ExecuteCommand(s *discordgo.Session, m *discordgo.MessageCrea
 		return err
 	}
 
-	return cmd.Execute(s, m, cmdArgs)
+	return cmd.Execute(newMessageContext(s, m, cmdArgs))
+}
+
+// maxDescriptionLength is the longest description Discord accepts for an application command
+const maxDescriptionLength = 100
+
+// ApplicationCommands describes every registered command as a Discord slash command
+func (r *Registry) ApplicationCommands() []*discordgo.ApplicationCommand {
+	appCommands := make([]*discordgo.ApplicationCommand, 0, len(r.commands))
+	for name, cmd := range r.commands {
+		description := cmd.Description()
+		if len(description) > maxDescriptionLength {
+			description = description[:maxDescriptionLength-3] + "..."
+		}
+
+		appCommands = append(appCommands, &discordgo.ApplicationCommand{
+			Name:        name,
+			Description: description,
+			Options:     cmd.Options(),
+		})
+	}
+	return appCommands
+}
+
+// ExecuteInteraction finds and executes the command for a slash command interaction
+func (r *Registry) ExecuteInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	data := i.ApplicationCommandData()
+
+	cmd, err := r.Get(data.Name)
+	if err != nil {
+		return err
+	}
+
+	return r.executeDeferred(s, i, cmd, optionArgs(data.Options))
+}
+
+// executeDeferred acknowledges an interaction and runs a command for it
+func (r *Registry) executeDeferred(s *discordgo.Session, i *discordgo.InteractionCreate, cmd Command, args []string) error {
+	return r.runDeferred(s, i, args, cmd.Execute)
+}
+
+// runDeferred acknowledges an interaction and runs fn to answer it
+func (r *Registry) runDeferred(s *discordgo.Session, i *discordgo.InteractionCreate, args []string, fn func(ctx Context) error) error {
+	// Acknowledge right away; Discord drops interactions not answered within three seconds
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge interaction: %w", err)
+	}
+
+	ctx := newInteractionContext(s, i, args)
+	err = fn(ctx)
+
+	// Commands that only answer by DM leave the deferred response pending; clear it
+	if err == nil && !ctx.replied {
+		err = s.InteractionResponseDelete(i.Interaction)
+	}
+
+	return err
+}
+
+// autocompleteTimeout bounds the lookup behind autocomplete suggestions; Discord stops waiting after three seconds
+const autocompleteTimeout = 2500 * time.Millisecond
+
+// maxAutocompleteChoices is the most suggestions Discord shows for an option
+const maxAutocompleteChoices = 25
+
+// Autocomplete answers an autocomplete interaction with the suggestions of the command being typed
+func (r *Registry) Autocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	data := i.ApplicationCommandData()
+
+	cmd, err := r.Get(data.Name)
+	if err != nil {
+		return err
+	}
+
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	completer, ok := cmd.(Autocompleter)
+	if ok {
+		for _, opt := range data.Options {
+			if !opt.Focused {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), autocompleteTimeout)
+			choices, err = completer.Autocomplete(ctx, interactionUser(i).ID, opt.Name, opt.StringValue())
+			cancel()
+			if err != nil {
+				// An empty list still has to be sent, or the user sees a loading error
+				log.Printf("[WARN] Autocomplete for /%s failed: %v", data.Name, err)
+				choices = nil
+			}
+			break
+		}
+	}
+
+	if len(choices) > maxAutocompleteChoices {
+		choices = choices[:maxAutocompleteChoices]
+	}
+	if choices == nil {
+		choices = []*discordgo.ApplicationCommandOptionChoice{}
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send autocomplete choices: %w", err)
+	}
+	return nil
+}
+
+// optionArgs converts slash command options into the positional arguments prefix commands receive
+func optionArgs(options []*discordgo.ApplicationCommandInteractionDataOption) []string {
+	args := make([]string, 0, len(options))
+	for _, opt := range options {
+		switch opt.Type {
+		case discordgo.ApplicationCommandOptionString:
+			args = append(args, opt.StringValue())
+		case discordgo.ApplicationCommandOptionInteger:
+			args = append(args, strconv.FormatInt(opt.IntValue(), 10))
+		case discordgo.ApplicationCommandOptionNumber:
+			args = append(args, strconv.FormatFloat(opt.FloatValue(), 'f', -1, 64))
+		case discordgo.ApplicationCommandOptionBoolean:
+			args = append(args, strconv.FormatBool(opt.BoolValue()))
+		default:
+			args = append(args, fmt.Sprint(opt.Value))
+		}
+	}
+	return args
 }
 
 // Add helper method to get all commands
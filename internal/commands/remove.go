@@ -1,14 +1,18 @@
 package commands
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"jam-bot/internal/spotify"
-	"strconv"
+	"regexp"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// userMentionPattern matches a Discord user mention and captures the user ID
+var userMentionPattern = regexp.MustCompile(`^<@!?(\d+)>$`)
+
 type RemoveCommand struct {
 	spotifyService *spotify.Service
 }
@@ -22,15 +26,26 @@ func (c *RemoveCommand) Name() string {
 }
 
 func (c *RemoveCommand) Description() string {
-	return "Removes a song from the queue by its position. Usage: !remove [position]"
+	return "Removes songs from the queue by position, range or user. Usage: !remove [position | from-to | @user]"
 }
 
-func (c *RemoveCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	channelID := m.ChannelID
+func (c *RemoveCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "songs",
+			Description: "A position (3), a range (3-6), or @user for all their upcoming songs",
+			Required:    true,
+		},
+	}
+}
+
+func (c *RemoveCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
 
 	if channelID == "" {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ This command can only be used within a channel.")
+		err := ctx.Reply("❌ This command can only be used within a channel.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -38,26 +53,57 @@ func (c *RemoveCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate
 	}
 
 	if len(args) != 1 {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ Please provide the position of the song to remove. Usage: `!remove [position]`")
+		err := ctx.Reply("❌ Please say which songs to remove. Usage: `!remove [position | from-to | @user]`")
 		if err != nil {
 			return fmt.Errorf("failed to send usage message: %w", err)
 		}
 		return nil
 	}
 
-	position, err := strconv.Atoi(args[0])
-	if err != nil || position < 1 {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ Invalid position. Please provide a positive integer.")
+	// Remove every upcoming song a user added
+	if match := userMentionPattern.FindStringSubmatch(args[0]); match != nil {
+		removed, err := c.spotifyService.RemoveSongsByUser(ctx.Context(), channelID, match[1])
+		if errors.Is(err, spotify.ErrSessionNotFound) {
+			return promptStart(ctx)
+		}
+		if err != nil {
+			sendErr := ctx.Reply(queueEditError("remove the songs", err))
+			if sendErr != nil {
+				return fmt.Errorf("failed to send error message: %w", sendErr)
+			}
+			return fmt.Errorf("failed to remove songs: %w", err)
+		}
+
+		err = ctx.Reply(fmt.Sprintf("✅ Removed **%d** upcoming song(s) added by <@%s>.", len(removed), match[1]))
+		if err != nil {
+			return fmt.Errorf("failed to send confirmation message: %w", err)
+		}
+		return nil
+	}
+
+	// Remove a single position or a range of positions
+	fromArg, toArg, isRange := strings.Cut(args[0], "-")
+	if !isRange {
+		toArg = fromArg
+	}
+
+	from, okFrom := parsePosition(fromArg)
+	to, okTo := parsePosition(toArg)
+	if !okFrom || !okTo || from > to {
+		err := ctx.Reply("❌ Invalid position. Please provide a positive integer or a range like `3-6`.")
 		if err != nil {
 			return fmt.Errorf("failed to send error message: %w", err)
 		}
 		return nil
 	}
 
-	// Remove the song from the queue
-	err = c.spotifyService.RemoveSongFromQueue(ctx, channelID, position-1)
+	// Remove the songs from the queue
+	removed, err := c.spotifyService.RemoveSongRange(ctx.Context(), channelID, from, to)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
 	if err != nil {
-		_, sendErr := s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("❌ Failed to remove the song: %v", err))
+		sendErr := ctx.Reply(queueEditError("remove the song", err))
 		if sendErr != nil {
 			return fmt.Errorf("failed to send error message: %w", sendErr)
 		}
@@ -65,7 +111,12 @@ func (c *RemoveCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate
 	}
 
 	// Confirm to the user
-	_, err = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✅ Song at position **%d** has been removed from the queue.", position))
+	message := fmt.Sprintf("✅ Removed **%d** songs (positions **%d-%d**) from the queue.", len(removed), from+1, to+1)
+	if len(removed) == 1 {
+		message = fmt.Sprintf("✅ **%s** at position **%d** has been removed from the queue.", removed[0].Title, from+1)
+	}
+
+	err = ctx.Reply(message)
 	if err != nil {
 		return fmt.Errorf("failed to send confirmation message: %w", err)
 	}
@@ -0,0 +1,122 @@
+package commands_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"jam-bot/internal/commands"
+	"jam-bot/internal/commands/commandstest"
+)
+
+func TestRemoveCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		noSession bool
+		wantReply string
+		wantErr   bool
+		wantQueue []string
+	}{
+		{
+			name:      "no session",
+			args:      []string{"1"},
+			noSession: true,
+			wantReply: "no jam session in this channel",
+		},
+		{
+			name:      "nothing given",
+			wantReply: "Usage:",
+			wantQueue: []string{"First Song", "Second Song", "Third Song"},
+		},
+		{
+			name:      "one position",
+			args:      []string{"2"},
+			wantReply: "**Second Song** at position **2** has been removed",
+			wantQueue: []string{"First Song", "Third Song"},
+		},
+		{
+			name:      "range",
+			args:      []string{"1-2"},
+			wantReply: "Removed **2** songs (positions **1-2**)",
+			wantQueue: []string{"Third Song"},
+		},
+		{
+			name:      "songs of a user",
+			args:      []string{"<@" + guestID + ">"},
+			wantReply: "Removed **1** upcoming song(s) added by <@" + guestID + ">",
+			wantQueue: []string{"First Song", "Third Song"},
+		},
+		{
+			name:      "past the end of the queue",
+			args:      []string{"4"},
+			wantReply: "There is no song at that position",
+			wantErr:   true,
+			wantQueue: []string{"First Song", "Second Song", "Third Song"},
+		},
+		{
+			name:      "backwards range",
+			args:      []string{"3-1"},
+			wantReply: "Invalid position",
+			wantQueue: []string{"First Song", "Second Song", "Third Song"},
+		},
+		{
+			name:      "not a position",
+			args:      []string{"first"},
+			wantReply: "Invalid position",
+			wantQueue: []string{"First Song", "Second Song", "Third Song"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			if !tt.noSession {
+				env.startSession(t, testSongs...)
+			}
+
+			ctx := commandstest.NewContext(guestID, "channel", tt.args...)
+			err := commands.NewRemoveCommand(env.svc).Execute(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute: %v, want error: %v", err, tt.wantErr)
+			}
+
+			if !strings.Contains(ctx.LastReply(), tt.wantReply) {
+				t.Errorf("reply = %q, want it to contain %q", ctx.LastReply(), tt.wantReply)
+			}
+			if tt.noSession {
+				return
+			}
+			if queue := env.queue(t); !reflect.DeepEqual(queue, tt.wantQueue) {
+				t.Errorf("queue = %v, want %v", queue, tt.wantQueue)
+			}
+		})
+	}
+}
+
+func TestRemoveCommandPlayingSong(t *testing.T) {
+	env := newTestEnv(t)
+	env.startSession(t, testSongs...)
+
+	_, err := env.svc.StartPlayback(context.Background(), "channel")
+	if err != nil {
+		t.Fatalf("StartPlayback: %v", err)
+	}
+
+	ctx := commandstest.NewContext(guestID, "channel", "1")
+	err = commands.NewRemoveCommand(env.svc).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	// Removing the playing song moves everyone on to the next one
+	if queue := env.queue(t); !reflect.DeepEqual(queue, []string{"Second Song", "Third Song"}) {
+		t.Errorf("queue = %v, want the first song gone", queue)
+	}
+	for _, user := range []string{"host", "guest"} {
+		if player := env.srv.Player(user); player.TrackURI != "spotify:track:second" || !player.IsPlaying {
+			t.Errorf("%s's player = %+v, want the second song playing", user, player)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// nextRepeatMode is the mode `!repeat` switches to when no mode is given
+var nextRepeatMode = map[spotify.RepeatMode]spotify.RepeatMode{
+	spotify.RepeatOff:   spotify.RepeatQueue,
+	spotify.RepeatQueue: spotify.RepeatTrack,
+	spotify.RepeatTrack: spotify.RepeatOff,
+}
+
+type RepeatCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewRepeatCommand(spotifyService *spotify.Service) *RepeatCommand {
+	return &RepeatCommand{spotifyService: spotifyService}
+}
+
+func (c *RepeatCommand) Name() string {
+	return "repeat"
+}
+
+func (c *RepeatCommand) Description() string {
+	return "Sets the repeat mode: off, track (replay the current song) or queue (replay the whole queue)."
+}
+
+func (c *RepeatCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "mode",
+			Description: "Repeat mode; cycles through the modes when left out",
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "off", Value: string(spotify.RepeatOff)},
+				{Name: "track", Value: string(spotify.RepeatTrack)},
+				{Name: "queue", Value: string(spotify.RepeatQueue)},
+			},
+		},
+	}
+}
+
+func (c *RepeatCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	if len(args) > 1 {
+		err := ctx.Reply("❌ Usage: `!repeat [off | track | queue]`")
+		if err != nil {
+			return fmt.Errorf("failed to send usage message: %w", err)
+		}
+		return nil
+	}
+
+	var mode spotify.RepeatMode
+	if len(args) == 1 {
+		var err error
+		mode, err = spotify.ParseRepeatMode(args[0])
+		if err != nil {
+			err = ctx.Reply("❌ Usage: `!repeat [off | track | queue]`")
+			if err != nil {
+				return fmt.Errorf("failed to send usage message: %w", err)
+			}
+			return nil
+		}
+	} else {
+		// Retrieve the current session to cycle its mode
+		session, err := c.spotifyService.LoadSession(ctx.Context(), channelID)
+		if errors.Is(err, spotify.ErrSessionNotFound) {
+			return promptStart(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load session: %w", err)
+		}
+		mode = nextRepeatMode[session.RepeatMode()]
+	}
+
+	err := c.spotifyService.SetRepeat(ctx.Context(), channelID, mode)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to change repeat mode: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to set repeat mode: %w", err)
+	}
+
+	var message string
+	switch mode {
+	case spotify.RepeatTrack:
+		message = "🔂 Repeating the current song."
+	case spotify.RepeatQueue:
+		message = "🔁 Repeating the queue. Finished songs go back to the end."
+	default:
+		message = "➡️ Repeat is off."
+	}
+
+	err = ctx.Reply(message)
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strings"
+)
+
+// reportSummary describes which participants a playback change reached, to append to a confirmation message
+func reportSummary(report spotify.PlaybackReport) string {
+	total := len(report.Results)
+	if total == 0 {
+		return ""
+	}
+
+	failed := report.Failed()
+	if len(failed) == 0 {
+		return fmt.Sprintf("\n🎧 Reached all %d participant(s).", total)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n⚠️ Reached %d/%d participant(s):", report.Succeeded(), total))
+	for _, result := range failed {
+		sb.WriteString(fmt.Sprintf("\n• <@%s>: %s", result.UserID, result.Problem()))
+	}
+	return sb.String()
+}
@@ -0,0 +1,327 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// searchMenuPrefix marks the custom IDs of search result menus
+const searchMenuPrefix = "search:"
+
+// searchResultLimit is the number of results offered for a search
+const searchResultLimit = 10
+
+// searchChoiceTimeout is how long search results can be picked from
+const searchChoiceTimeout = 2 * time.Minute
+
+// minAutocompleteQuery is the shortest query autocomplete searches Spotify for
+const minAutocompleteQuery = 2
+
+// maxChoiceLength is the longest menu label, menu description or autocomplete name Discord accepts
+const maxChoiceLength = 100
+
+// pendingSearch is a set of search results waiting for its searcher to pick one
+type pendingSearch struct {
+	userID    string
+	channelID string
+	songs     []spotify.Song
+	expiresAt time.Time
+}
+
+// SearchCommand shows the top Spotify results for a query and queues the one picked from a menu.
+// As a slash command it also suggests songs while the query is typed.
+type SearchCommand struct {
+	spotifyService *spotify.Service
+	registry       *Registry
+
+	mu      sync.Mutex
+	pending map[string]*pendingSearch // menu ID -> results
+}
+
+func NewSearchCommand(spotifyService *spotify.Service, registry *Registry) *SearchCommand {
+	return &SearchCommand{
+		spotifyService: spotifyService,
+		registry:       registry,
+		pending:        make(map[string]*pendingSearch),
+	}
+}
+
+func (c *SearchCommand) Name() string {
+	return "search"
+}
+
+func (c *SearchCommand) Description() string {
+	return "Searches Spotify and lets you pick which result to add to the queue. Usage: !search [query]"
+}
+
+func (c *SearchCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "query",
+			Description:  "The song to search for",
+			Required:     true,
+			Autocomplete: true,
+		},
+	}
+}
+
+func (c *SearchCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	if len(args) == 0 {
+		err := ctx.Reply("❌ Please provide something to search for. Usage: `!search [query]`")
+		if err != nil {
+			return fmt.Errorf("failed to send usage message: %w", err)
+		}
+		return nil
+	}
+
+	query := strings.Join(args, " ")
+
+	// Songs can only be queued into a running session, so don't go to Spotify without one
+	_, err := c.spotifyService.LoadSession(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	// Autocomplete suggestions fill in the track URI, which needs no picking
+	if link, ok := spotify.ParseLink(query); ok && link.Kind == spotify.LinkTrack {
+		return c.addLinkedTrack(ctx, channelID, link)
+	}
+
+	// Search for the songs using Spotify API
+	songs, err := c.spotifyService.SearchSongs(ctx.Context(), ctx.Author().ID, query, searchResultLimit)
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to search: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to search songs: %w", err)
+	}
+
+	if len(songs) == 0 {
+		err := ctx.Reply(fmt.Sprintf("❌ No results found for **%s**.", query))
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	menuID := c.addPending(&pendingSearch{
+		userID:    ctx.Author().ID,
+		channelID: channelID,
+		songs:     songs,
+		expiresAt: time.Now().Add(searchChoiceTimeout),
+	})
+
+	var lines []string
+	options := make([]MenuOption, 0, len(songs))
+	for idx, song := range songs {
+		lines = append(lines, fmt.Sprintf("%d. **%s** by **%s** (%s)", idx+1, song.Title, song.Artist, formatDuration(song.DurationMs)))
+		options = append(options, MenuOption{
+			Label:       truncate(fmt.Sprintf("%d. %s", idx+1, song.Title), maxChoiceLength),
+			Description: truncate(song.Artist, maxChoiceLength),
+			Value:       strconv.Itoa(idx),
+		})
+	}
+
+	err = ctx.ReplyMenu(Embed{
+		Title:       fmt.Sprintf("🔎 Results for \"%s\"", truncate(query, maxChoiceLength)),
+		Description: strings.Join(lines, "\n"),
+		Footer:      fmt.Sprintf("Pick a song within %d minutes", int(searchChoiceTimeout.Minutes())),
+	}, Menu{
+		ID:          menuID,
+		Placeholder: "Choose a song to add",
+		Options:     options,
+	})
+	if err != nil {
+		c.removePending(menuID)
+		return fmt.Errorf("failed to send search results: %w", err)
+	}
+
+	return nil
+}
+
+// Autocomplete suggests Spotify search results for the query being typed
+func (c *SearchCommand) Autocomplete(ctx context.Context, userID, option, value string) ([]*discordgo.ApplicationCommandOptionChoice, error) {
+	value = strings.TrimSpace(value)
+	if option != "query" || utf8.RuneCountInString(value) < minAutocompleteQuery {
+		return nil, nil
+	}
+
+	// Users who haven't linked Spotify can still type a query and pick from the menu
+	if authenticated, err := c.spotifyService.IsAuthenticated(ctx, userID); err != nil || !authenticated {
+		return nil, err
+	}
+
+	songs, err := c.spotifyService.SearchSongs(ctx, userID, value, searchResultLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(songs))
+	for _, song := range songs {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  truncate(fmt.Sprintf("%s - %s", song.Title, song.Artist), maxChoiceLength),
+			Value: song.URI,
+		})
+	}
+	return choices, nil
+}
+
+// HandlesComponent reports whether a message component custom ID belongs to a search result menu
+func (c *SearchCommand) HandlesComponent(customID string) bool {
+	return strings.HasPrefix(customID, searchMenuPrefix)
+}
+
+// HandleComponent queues the song picked from a search result menu
+func (c *SearchCommand) HandleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	data := i.MessageComponentData()
+	if !c.HandlesComponent(data.CustomID) {
+		return errors.New("not a search result menu")
+	}
+
+	return c.registry.runDeferred(s, i, data.Values, func(ctx Context) error {
+		return c.pick(ctx, data.CustomID)
+	})
+}
+
+// pick queues the song chosen from the results behind menuID
+func (c *SearchCommand) pick(ctx Context, menuID string) error {
+	c.mu.Lock()
+	search, ok := c.pending[menuID]
+	if ok && search.userID == ctx.Author().ID {
+		delete(c.pending, menuID)
+	}
+	c.mu.Unlock()
+
+	if !ok || time.Now().After(search.expiresAt) {
+		err := ctx.Reply("⌛ These search results have expired. Run `!search` again.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	if search.userID != ctx.Author().ID {
+		err := ctx.Reply(fmt.Sprintf("❌ Only <@%s> can pick from these results. Run `!search` to search yourself.", search.userID))
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	args := ctx.Args()
+	index := -1
+	if len(args) == 1 {
+		index, _ = strconv.Atoi(args[0])
+	}
+	if index < 0 || index >= len(search.songs) {
+		return fmt.Errorf("invalid search result %v", args)
+	}
+
+	song := search.songs[index]
+	song.AddedBy = ctx.Author().ID
+
+	return c.addSong(ctx, search.channelID, song)
+}
+
+// addLinkedTrack queues the track a Spotify link points to
+func (c *SearchCommand) addLinkedTrack(ctx Context, channelID string, link spotify.Link) error {
+	resolved, err := c.spotifyService.ResolveLink(ctx.Context(), ctx.Author().ID, link)
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to find the song: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to resolve link: %w", err)
+	}
+
+	song := resolved.Songs[0]
+	song.AddedBy = ctx.Author().ID
+
+	return c.addSong(ctx, channelID, song)
+}
+
+// addSong adds a song to the channel's queue and confirms it
+func (c *SearchCommand) addSong(ctx Context, channelID string, song spotify.Song) error {
+	err := c.spotifyService.AddSongToQueue(ctx.Context(), channelID, song)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if message, ok := policyRejection(err); ok {
+		err = ctx.Reply(message)
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to add the song to the queue: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to add song to queue: %w", err)
+	}
+
+	err = ctx.Reply(fmt.Sprintf("✅ **%s** by **%s** has been added to the queue.", song.Title, song.Artist))
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
+
+// addPending stores search results until they are picked from or expire, and returns their menu ID
+func (c *SearchCommand) addPending(search *pendingSearch) string {
+	menuID := searchMenuPrefix + strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	c.mu.Lock()
+	c.pending[menuID] = search
+	c.mu.Unlock()
+
+	time.AfterFunc(time.Until(search.expiresAt), func() {
+		c.removePending(menuID)
+	})
+
+	return menuID
+}
+
+// removePending forgets search results
+func (c *SearchCommand) removePending(menuID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, menuID)
+}
+
+// truncate shortens text to at most max characters, marking the cut with an ellipsis
+func truncate(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type SeekCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewSeekCommand(spotifyService *spotify.Service) *SeekCommand {
+	return &SeekCommand{spotifyService: spotifyService}
+}
+
+func (c *SeekCommand) Name() string {
+	return "seek"
+}
+
+func (c *SeekCommand) Description() string {
+	return "Jumps to a position in the current song, e.g. 1:30, +15s or -10s."
+}
+
+func (c *SeekCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "position",
+			Description: "A position like 1:30, or an offset like +15s or -10s",
+			Required:    true,
+		},
+	}
+}
+
+func (c *SeekCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	if len(args) != 1 {
+		err := ctx.Reply("❌ Please provide a position. Usage: `!seek [1:30 | +15s | -10s]`")
+		if err != nil {
+			return fmt.Errorf("failed to send usage message: %w", err)
+		}
+		return nil
+	}
+
+	positionMs, relative, err := parseSeekPosition(args[0])
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ %v. Usage: `!seek [1:30 | +15s | -10s]`", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send usage message: %w", sendErr)
+		}
+		return nil
+	}
+
+	// Seek for everyone
+	report, err := c.spotifyService.SeekPlayback(ctx.Context(), channelID, positionMs, relative)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		message := fmt.Sprintf("❌ Failed to seek: %v", err)
+		if errors.Is(err, spotify.ErrSeekOutOfRange) {
+			message = "❌ That position is past the end of the song."
+		}
+
+		sendErr := ctx.Reply(message)
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to seek: %w", err)
+	}
+
+	// Confirm to the user
+	err = ctx.Reply(fmt.Sprintf("⏩ Jumped to **%s** in **%s**.", formatDuration(report.PositionMs), report.Song.Title) + reportSummary(report))
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
+
+// parseSeekPosition parses an absolute position (1:30, 1:02:03, 90) or a signed offset (+15s, -10s, +1:00)
+// into milliseconds. Plain numbers are seconds; units follow time.ParseDuration (15s, 1m30s).
+func parseSeekPosition(arg string) (int, bool, error) {
+	arg = strings.TrimSpace(arg)
+	relative := strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-")
+	negative := strings.HasPrefix(arg, "-")
+	value := strings.TrimLeft(arg, "+-")
+
+	if value == "" {
+		return 0, false, errors.New("missing position")
+	}
+
+	var ms int
+	switch {
+	case strings.Contains(value, ":"):
+		// [h:]m:ss
+		seconds := 0
+		parts := strings.Split(value, ":")
+		if len(parts) > 3 {
+			return 0, false, fmt.Errorf("invalid position %q", arg)
+		}
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 || (i > 0 && n >= 60) {
+				return 0, false, fmt.Errorf("invalid position %q", arg)
+			}
+			seconds = seconds*60 + n
+		}
+		ms = seconds * 1000
+	default:
+		if seconds, err := strconv.Atoi(value); err == nil {
+			ms = seconds * 1000
+			break
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return 0, false, fmt.Errorf("invalid position %q", arg)
+		}
+		ms = int(d.Milliseconds())
+	}
+
+	if negative {
+		ms = -ms
+	}
+	return ms, relative, nil
+}
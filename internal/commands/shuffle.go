@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type ShuffleCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewShuffleCommand(spotifyService *spotify.Service) *ShuffleCommand {
+	return &ShuffleCommand{spotifyService: spotifyService}
+}
+
+func (c *ShuffleCommand) Name() string {
+	return "shuffle"
+}
+
+func (c *ShuffleCommand) Description() string {
+	return "Turns shuffle on or off for the queue. Give a seed to repeat a previous shuffle."
+}
+
+func (c *ShuffleCommand) Options() []*discordgo.ApplicationCommandOption {
+	minSeed := 1.0
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "mode",
+			Description: "Turn shuffle on or off; toggles when left out",
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "on", Value: "on"},
+				{Name: "off", Value: "off"},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "seed",
+			Description: "Seed of a previous shuffle to reproduce",
+			MinValue:    &minSeed,
+		},
+	}
+}
+
+func (c *ShuffleCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	// Work out the requested mode and seed; a bare seed turns shuffle on
+	var mode string
+	var seed int64
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "on", "off":
+			mode = strings.ToLower(arg)
+			continue
+		}
+
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || n <= 0 {
+			err = ctx.Reply("❌ Usage: `!shuffle [on | off] [seed]`")
+			if err != nil {
+				return fmt.Errorf("failed to send usage message: %w", err)
+			}
+			return nil
+		}
+		seed = n
+		mode = "on"
+	}
+
+	// Retrieve the current session
+	session, err := c.spotifyService.LoadSession(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	enabled := !session.Shuffle
+	if mode != "" {
+		enabled = mode == "on"
+	}
+
+	session, err = c.spotifyService.SetShuffle(ctx.Context(), channelID, enabled, seed)
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to change shuffle: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to set shuffle: %w", err)
+	}
+
+	message := "➡️ Shuffle is off. Upcoming songs are back in the order they were added."
+	if session.Shuffle {
+		message = fmt.Sprintf("🔀 Shuffle is on (seed **%d**). Use `!shuffle %d` to get this order again.", session.ShuffleSeed, session.ShuffleSeed)
+	}
+
+	err = ctx.Reply(message)
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SkipCommand advances the jam session to the next song in the queue.
+// It is registered under both "skip" and "next".
+type SkipCommand struct {
+	name           string
+	spotifyService *spotify.Service
+}
+
+func NewSkipCommand(spotifyService *spotify.Service) *SkipCommand {
+	return &SkipCommand{name: "skip", spotifyService: spotifyService}
+}
+
+func NewNextCommand(spotifyService *spotify.Service) *SkipCommand {
+	return &SkipCommand{name: "next", spotifyService: spotifyService}
+}
+
+func (c *SkipCommand) Name() string {
+	return c.name
+}
+
+func (c *SkipCommand) Description() string {
+	return "Skips to the next song in the queue for everyone in the jam session."
+}
+
+func (c *SkipCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *SkipCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	// Skip to the next song
+	report, err := c.spotifyService.SkipTrack(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to skip the song: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to skip song: %w", err)
+	}
+
+	// Confirm to the user
+	message := fmt.Sprintf("⏭️ Skipped! Now playing **%s** by **%s**.", report.Song.Title, report.Song.Artist)
+	switch {
+	case report.Finished:
+		message = "⏹️ Skipped! That was the last song, so the queue is finished. Add more with `!add`."
+	case report.Paused:
+		message = fmt.Sprintf("⏭️ Skipped! **%s** by **%s** is up next. Use `!play` to start it.", report.Song.Title, report.Song.Artist)
+	}
+
+	err = ctx.Reply(message + reportSummary(report))
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
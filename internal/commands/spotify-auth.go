@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"context"
 	"fmt"
 	"jam-bot/internal/spotify"
 
@@ -24,21 +23,19 @@ func (c *SpotifyAuthCommand) Description() string {
 	return "Authenticate with Spotify"
 }
 
-func (c *SpotifyAuthCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	isAuth, err := c.spotifyService.IsAuthenticated(ctx, m.Author.ID)
+func (c *SpotifyAuthCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *SpotifyAuthCommand) Execute(ctx Context) error {
+	isAuth, err := c.spotifyService.IsAuthenticated(ctx.Context(), ctx.Author().ID)
 	if err != nil {
 		return fmt.Errorf("failed to check authentication status: %w", err)
 	}
 
 	if isAuth {
 		// User is already authenticated
-		dm, err := s.UserChannelCreate(m.Author.ID)
-		if err != nil {
-			return fmt.Errorf("failed to create DM channel: %w", err)
-		}
-
-		_, err = s.ChannelMessageSend(dm.ID, "You are already authenticated with Spotify!")
+		err = ctx.DM("You are already authenticated with Spotify!")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -47,14 +44,9 @@ func (c *SpotifyAuthCommand) Execute(s *discordgo.Session, m *discordgo.MessageC
 	}
 
 	// User is not authenticated; proceed with auth flow
-	authURL := c.spotifyService.GetAuthURL(m.Author.ID)
-
-	dm, err := s.UserChannelCreate(m.Author.ID)
-	if err != nil {
-		return fmt.Errorf("failed to create DM channel: %w", err)
-	}
+	authURL := c.spotifyService.GetAuthURL(ctx.Author().ID)
 
-	_, err = s.ChannelMessageSend(dm.ID, fmt.Sprintf(
+	err = ctx.DM(fmt.Sprintf(
 		"Please authenticate with Spotify by clicking this link:\n%s",
 		authURL,
 	))
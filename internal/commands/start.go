@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type StartCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewStartCommand(spotifyService *spotify.Service) *StartCommand {
+	return &StartCommand{spotifyService: spotifyService}
+}
+
+func (c *StartCommand) Name() string {
+	return "start"
+}
+
+func (c *StartCommand) Description() string {
+	return "Starts a jam session in this channel with you as the host. Usage: !start [shuffle] [fair] [repeat track | queue]"
+}
+
+func (c *StartCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "settings",
+			Description: "Play modes to start with, e.g. \"shuffle fair repeat queue\"",
+		},
+	}
+}
+
+func (c *StartCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+	userID := ctx.Author().ID
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	// Slash commands pass the settings as one argument
+	settings, err := parseSessionSettings(strings.Fields(strings.Join(ctx.Args(), " ")))
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ %v. Usage: `!start [shuffle] [fair] [repeat track | queue]`", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send usage message: %w", sendErr)
+		}
+		return nil
+	}
+
+	// The host joins right away, so they have to be able to play
+	isAuth, err := c.spotifyService.IsAuthenticated(ctx.Context(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to check authentication status: %w", err)
+	}
+
+	if !isAuth {
+		err = ctx.DM("You need to authenticate with Spotify first. Use `!auth` to authenticate.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	session, err := c.spotifyService.StartSession(ctx.Context(), ctx.GuildID(), channelID, userID, settings)
+	if errors.Is(err, spotify.ErrSessionExists) {
+		message := "❌ A jam session is already running in this channel. Use `!join` to join it."
+		if existing, loadErr := c.spotifyService.LoadSession(ctx.Context(), channelID); loadErr == nil && existing.Host != "" {
+			message = fmt.Sprintf("❌ <@%s> is already hosting a jam session in this channel. Use `!join` to join it.", existing.Host)
+		}
+		err = ctx.Reply(message)
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to start the jam session: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+
+	message := fmt.Sprintf("🎉 %s started a jam session! Use `!join` to listen along and `!add` to queue songs.", ctx.Author().Mention())
+	if modes := playModes(session); modes != "" {
+		message += "\n" + modes
+	}
+
+	err = ctx.Reply(message)
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
+
+// parseSessionSettings reads the play modes a session should start with
+func parseSessionSettings(words []string) (spotify.SessionSettings, error) {
+	var settings spotify.SessionSettings
+	for i := 0; i < len(words); i++ {
+		switch word := strings.ToLower(words[i]); word {
+		case "shuffle":
+			settings.Shuffle = true
+		case "fair":
+			settings.Fair = true
+		case "repeat":
+			// A bare "repeat" repeats the queue
+			settings.Repeat = spotify.RepeatQueue
+			if i+1 < len(words) {
+				if mode, err := spotify.ParseRepeatMode(words[i+1]); err == nil {
+					settings.Repeat = mode
+					i++
+				}
+			}
+		default:
+			return spotify.SessionSettings{}, fmt.Errorf("unknown setting %q", word)
+		}
+	}
+	return settings, nil
+}
+
+// noSessionMessage tells the user a channel has no jam session and how to start one
+const noSessionMessage = "❌ There is no jam session in this channel yet. Start one with `!start`, then others can `!join`."
+
+// promptStart replies that the channel has no jam session and how to start one
+func promptStart(ctx Context) error {
+	err := ctx.Reply(noSessionMessage)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"context"
 	"fmt"
 	"jam-bot/internal/spotify"
 
@@ -24,22 +23,20 @@ func (c *SpotifyStatusCommand) Description() string {
 	return "Check Spotify authentication status"
 }
 
-func (c *SpotifyStatusCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	isAuth, err := c.spotifyService.IsAuthenticated(ctx, m.Author.ID)
-	if err != nil {
-		return fmt.Errorf("failed to check authentication status: %w", err)
-	}
+func (c *SpotifyStatusCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
 
-	dm, err := s.UserChannelCreate(m.Author.ID)
+func (c *SpotifyStatusCommand) Execute(ctx Context) error {
+	isAuth, err := c.spotifyService.IsAuthenticated(ctx.Context(), ctx.Author().ID)
 	if err != nil {
-		return fmt.Errorf("failed to create DM channel: %w", err)
+		return fmt.Errorf("failed to check authentication status: %w", err)
 	}
 
 	if isAuth {
-		_, err = s.ChannelMessageSend(dm.ID, "You are already authenticated with Spotify!")
+		err = ctx.DM("You are already authenticated with Spotify!")
 	} else {
-		_, err = s.ChannelMessageSend(dm.ID, "You are not authenticated with Spotify. Use `!auth` to authenticate.")
+		err = ctx.DM("You are not authenticated with Spotify. Use `!auth` to authenticate.")
 	}
 
 	if err != nil {
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type SwapCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewSwapCommand(spotifyService *spotify.Service) *SwapCommand {
+	return &SwapCommand{spotifyService: spotifyService}
+}
+
+func (c *SwapCommand) Name() string {
+	return "swap"
+}
+
+func (c *SwapCommand) Description() string {
+	return "Swaps two songs in the queue."
+}
+
+func (c *SwapCommand) Options() []*discordgo.ApplicationCommandOption {
+	minPosition := 1.0
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "first",
+			Description: "Position of the first song",
+			Required:    true,
+			MinValue:    &minPosition,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "second",
+			Description: "Position of the second song",
+			Required:    true,
+			MinValue:    &minPosition,
+		},
+	}
+}
+
+func (c *SwapCommand) Execute(ctx Context) error {
+	args := ctx.Args()
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	if len(args) != 2 {
+		err := ctx.Reply("❌ Please provide two positions. Usage: `!swap [first] [second]`")
+		if err != nil {
+			return fmt.Errorf("failed to send usage message: %w", err)
+		}
+		return nil
+	}
+
+	a, okA := parsePosition(args[0])
+	b, okB := parsePosition(args[1])
+	if !okA || !okB {
+		err := ctx.Reply("❌ Invalid position. Please provide positive integers.")
+		if err != nil {
+			return fmt.Errorf("failed to send error message: %w", err)
+		}
+		return nil
+	}
+
+	// Swap the songs
+	first, second, err := c.spotifyService.SwapSongs(ctx.Context(), channelID, a, b)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		sendErr := ctx.Reply(queueEditError("swap the songs", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to swap songs: %w", err)
+	}
+
+	// Confirm to the user
+	err = ctx.Reply(fmt.Sprintf("✅ **%s** is now at position **%d** and **%s** at position **%d**.", first.Title, a+1, second.Title, b+1) + manualOrderNote)
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type SyncStatsCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewSyncStatsCommand(spotifyService *spotify.Service) *SyncStatsCommand {
+	return &SyncStatsCommand{spotifyService: spotifyService}
+}
+
+func (c *SyncStatsCommand) Name() string {
+	return "syncstats"
+}
+
+func (c *SyncStatsCommand) Description() string {
+	return "Shows how far each participant has drifted from the jam session."
+}
+
+func (c *SyncStatsCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *SyncStatsCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	// Retrieve the participants
+	participants, err := c.spotifyService.GetSessionParticipants(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get session participants: %w", err)
+	}
+
+	if len(participants) == 0 {
+		err = ctx.Reply("There are no participants in the jam session.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	stats := c.spotifyService.SyncStats(channelID)
+
+	var sb strings.Builder
+	for _, userID := range participants {
+		s, ok := stats[userID]
+		if !ok {
+			sb.WriteString(fmt.Sprintf("<@%s>: no measurements yet\n", userID))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("<@%s>: **%+d ms** now, avg %d ms, max %d ms · latency %d ms · %d correction(s) in %d sample(s), last %s ago\n",
+			userID, s.LastDriftMs, s.MeanAbsDriftMs, s.MaxAbsDriftMs, s.LatencyMs, s.Corrections, s.Samples,
+			time.Since(s.LastSampleAt).Round(time.Second)))
+	}
+
+	err = ctx.ReplyEmbed(Embed{
+		Title:       "🔄 Playback Sync",
+		Description: sb.String(),
+		Footer:      fmt.Sprintf("Listeners more than %d ms off are seeked back in line.", c.spotifyService.SyncTolerance().Milliseconds()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send sync stats: %w", err)
+	}
+
+	return nil
+}
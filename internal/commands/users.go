@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"jam-bot/internal/spotify"
 
@@ -24,12 +24,15 @@ func (c *UsersCommand) Description() string {
 	return "Lists all users currently in the jam session."
 }
 
-func (c *UsersCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ctx := context.Background()
-	channelID := m.ChannelID
+func (c *UsersCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *UsersCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
 
 	if channelID == "" {
-		_, err := s.ChannelMessageSend(m.ChannelID, "❌ This command can only be used within a channel.")
+		err := ctx.Reply("❌ This command can only be used within a channel.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -37,33 +40,31 @@ func (c *UsersCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 	}
 
 	// Retrieve session participants using ChannelID
-	participants, err := c.spotifyService.GetSessionParticipants(ctx, channelID)
+	participants, err := c.spotifyService.GetSessionParticipants(ctx.Context(), channelID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get session participants: %w", err)
 	}
 
 	if len(participants) == 0 {
-		_, err := s.ChannelMessageSend(m.ChannelID, "👥 There are currently no users in the jam session.")
+		err := ctx.Reply("👥 There are currently no users in the jam session.")
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
 		return nil
 	}
 
-	// Fetch user details from Discord
+	// Mention each participant; the chat client resolves the names
 	var userMentions []string
 	for _, userID := range participants {
-		user, err := s.User(userID)
-		if err != nil {
-			userMentions = append(userMentions, fmt.Sprintf("Unknown User (%s)", userID))
-			continue
-		}
-		userMentions = append(userMentions, user.Mention())
+		userMentions = append(userMentions, User{ID: userID}.Mention())
 	}
 
-	usersMessage := "👥 **Current Jam Session Participants:**\n" + "<" + stringJoin(userMentions, ">, <") + ">"
+	usersMessage := "👥 **Current Jam Session Participants:**\n" + stringJoin(userMentions, ", ")
 
-	_, err = s.ChannelMessageSend(m.ChannelID, usersMessage)
+	err = ctx.Reply(usersMessage)
 	if err != nil {
 		return fmt.Errorf("failed to send users message: %w", err)
 	}
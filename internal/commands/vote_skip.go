@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"jam-bot/internal/spotify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type VoteSkipCommand struct {
+	spotifyService *spotify.Service
+}
+
+func NewVoteSkipCommand(spotifyService *spotify.Service) *VoteSkipCommand {
+	return &VoteSkipCommand{spotifyService: spotifyService}
+}
+
+func (c *VoteSkipCommand) Name() string {
+	return "vote_skip"
+}
+
+func (c *VoteSkipCommand) Description() string {
+	return "Votes to skip the current song. The song is skipped once enough participants agree."
+}
+
+func (c *VoteSkipCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (c *VoteSkipCommand) Execute(ctx Context) error {
+	channelID := ctx.ChannelID()
+
+	if channelID == "" {
+		err := ctx.Reply("❌ This command can only be used within a channel.")
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	}
+
+	// Cast the vote
+	tally, err := c.spotifyService.CastSkipVote(ctx.Context(), channelID, ctx.Author().ID)
+	if errors.Is(err, spotify.ErrSessionNotFound) {
+		return promptStart(ctx)
+	}
+	if err != nil {
+		sendErr := ctx.Reply(fmt.Sprintf("❌ Failed to vote: %v", err))
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error message: %w", sendErr)
+		}
+		return fmt.Errorf("failed to cast skip vote: %w", err)
+	}
+
+	var message string
+	switch {
+	case tally.Skipped && tally.Report.Finished:
+		message = fmt.Sprintf("⏹️ Vote passed (**%d/%d**)! Skipped **%s**. That was the last song, so the queue is finished.",
+			tally.Votes, tally.Required, tally.Song.Title) + reportSummary(tally.Report)
+	case tally.Skipped && tally.Report.Paused:
+		message = fmt.Sprintf("⏭️ Vote passed (**%d/%d**)! Skipped **%s**. **%s** by **%s** is up next; use `!play` to start it.",
+			tally.Votes, tally.Required, tally.Song.Title, tally.NextSong.Title, tally.NextSong.Artist)
+	case tally.Skipped && tally.NextSong.URI == "":
+		message = fmt.Sprintf("⏭️ Vote passed (**%d/%d**)! Skipped **%s**.", tally.Votes, tally.Required, tally.Song.Title)
+	case tally.Skipped:
+		message = fmt.Sprintf("⏭️ Vote passed (**%d/%d**)! Skipped **%s**. Now playing **%s** by **%s**.",
+			tally.Votes, tally.Required, tally.Song.Title, tally.NextSong.Title, tally.NextSong.Artist) + reportSummary(tally.Report)
+	case tally.AlreadyVoted:
+		message = fmt.Sprintf("🗳️ You already voted to skip **%s**. Votes: **%d/%d**.",
+			tally.Song.Title, tally.Votes, tally.Required)
+	default:
+		message = fmt.Sprintf("🗳️ Vote counted! **%d/%d** votes to skip **%s**.",
+			tally.Votes, tally.Required, tally.Song.Title)
+	}
+
+	err = ctx.Reply(message)
+	if err != nil {
+		return fmt.Errorf("failed to send vote message: %w", err)
+	}
+
+	return nil
+}
@@ -2,20 +2,35 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
+// Storage backends for tokens and sessions
+const (
+	StorageRedis  = "redis"
+	StorageMemory = "memory"
+)
+
 type Config struct {
-	DiscordToken        string
-	SpotifyClientID     string
-	SpotifyClientSecret string
-	SpotifyRedirectURI  string
-	BotPrefix           string
-	RedisAddr           string
-	RedisPassword       string
-	RedisDB             int
-	Port                int // Added Port field
+	DiscordToken           string
+	SpotifyClientID        string
+	SpotifyClientSecret    string
+	SpotifyRedirectURI     string
+	SpotifyAPIBaseURL      string // Base URL of the Spotify Web API, without a trailing slash
+	SpotifyAccountsBaseURL string // Base URL of the Spotify accounts (OAuth) service
+	BotPrefix              string
+	StorageBackend         string // "redis" or "memory"
+	RedisAddr              string
+	RedisPassword          string
+	RedisDB                int
+	Port                   int     // Added Port field
+	VoteSkipThreshold      float64 // Fraction of participants needed to skip a song
+	SpotifyRateLimit       float64 // Average Spotify API requests per second across the whole app
+	SpotifyRateBurst       int     // Spotify API requests allowed in a burst
+	SyncIntervalSeconds    int     // How often listeners are checked for drift; 0 disables syncing
+	SyncToleranceMs        int     // How far a listener may drift before being corrected
 }
 
 func LoadConfig() (*Config, error) {
@@ -29,18 +44,34 @@ func LoadConfig() (*Config, error) {
 	viper.BindEnv("SPOTIFY_CLIENT_ID")
 	viper.BindEnv("SPOTIFY_CLIENT_SECRET")
 	viper.BindEnv("SPOTIFY_REDIRECT_URI")
+	viper.BindEnv("SPOTIFY_API_BASE_URL")
+	viper.BindEnv("SPOTIFY_ACCOUNTS_BASE_URL")
 	viper.BindEnv("BOT_PREFIX")
+	viper.BindEnv("STORAGEBACKEND")
 	viper.BindEnv("REDISADDR") // Environment variables are case-insensitive
 	viper.BindEnv("REDISPASSWORD")
 	viper.BindEnv("REDISDB")
 	viper.BindEnv("PORT")        // Bind PORT environment variable
 	viper.BindEnv("SERVER_PORT") // Bind PORT environment variable
+	viper.BindEnv("VOTESKIPTHRESHOLD")
+	viper.BindEnv("SPOTIFYRATELIMIT")
+	viper.BindEnv("SPOTIFYRATEBURST")
+	viper.BindEnv("SYNCINTERVALSECONDS")
+	viper.BindEnv("SYNCTOLERANCEMS")
 
 	viper.SetDefault("BotPrefix", "!")
+	viper.SetDefault("SPOTIFY_API_BASE_URL", "https://api.spotify.com/v1")
+	viper.SetDefault("SPOTIFY_ACCOUNTS_BASE_URL", "https://accounts.spotify.com")
+	viper.SetDefault("StorageBackend", StorageRedis)
 	viper.SetDefault("RedisAddr", "localhost:6379")
 	viper.SetDefault("RedisPassword", "")
 	viper.SetDefault("RedisDB", 0)
 	viper.SetDefault("Port", 8080) // Default port
+	viper.SetDefault("VoteSkipThreshold", 0.5)
+	viper.SetDefault("SpotifyRateLimit", 10)
+	viper.SetDefault("SpotifyRateBurst", 20)
+	viper.SetDefault("SyncIntervalSeconds", 10)
+	viper.SetDefault("SyncToleranceMs", 1500)
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -52,15 +83,23 @@ func LoadConfig() (*Config, error) {
 	}
 
 	config := &Config{
-		DiscordToken:        viper.GetString("DISCORD_TOKEN"),
-		SpotifyClientID:     viper.GetString("SPOTIFY_CLIENT_ID"),
-		SpotifyClientSecret: viper.GetString("SPOTIFY_CLIENT_SECRET"),
-		SpotifyRedirectURI:  viper.GetString("SPOTIFY_REDIRECT_URI"),
-		BotPrefix:           viper.GetString("BotPrefix"),
-		RedisAddr:           viper.GetString("RedisAddr"),
-		RedisPassword:       viper.GetString("RedisPassword"),
-		RedisDB:             viper.GetInt("RedisDB"),
-		Port:                viper.GetInt("Port"), // Load Port
+		DiscordToken:           viper.GetString("DISCORD_TOKEN"),
+		SpotifyClientID:        viper.GetString("SPOTIFY_CLIENT_ID"),
+		SpotifyClientSecret:    viper.GetString("SPOTIFY_CLIENT_SECRET"),
+		SpotifyRedirectURI:     viper.GetString("SPOTIFY_REDIRECT_URI"),
+		SpotifyAPIBaseURL:      strings.TrimSuffix(viper.GetString("SPOTIFY_API_BASE_URL"), "/"),
+		SpotifyAccountsBaseURL: strings.TrimSuffix(viper.GetString("SPOTIFY_ACCOUNTS_BASE_URL"), "/"),
+		BotPrefix:              viper.GetString("BotPrefix"),
+		StorageBackend:         viper.GetString("StorageBackend"),
+		RedisAddr:              viper.GetString("RedisAddr"),
+		RedisPassword:          viper.GetString("RedisPassword"),
+		RedisDB:                viper.GetInt("RedisDB"),
+		Port:                   viper.GetInt("Port"), // Load Port
+		VoteSkipThreshold:      viper.GetFloat64("VoteSkipThreshold"),
+		SpotifyRateLimit:       viper.GetFloat64("SpotifyRateLimit"),
+		SpotifyRateBurst:       viper.GetInt("SpotifyRateBurst"),
+		SyncIntervalSeconds:    viper.GetInt("SyncIntervalSeconds"),
+		SyncToleranceMs:        viper.GetInt("SyncToleranceMs"),
 	}
 
 	return config, nil
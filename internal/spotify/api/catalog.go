@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxTracksPerRequest is the most track IDs the several-tracks endpoint accepts
+const maxTracksPerRequest = 50
+
+// Track returns a single track
+func (c *Client) Track(ctx context.Context, trackID string) (*Track, error) {
+	var track Track
+	err := c.do(ctx, http.MethodGet, "/tracks/"+url.PathEscape(trackID), nil, nil, &track)
+	if err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
+
+// Tracks returns several tracks by ID, in batches the API accepts. IDs that don't exist are left out.
+func (c *Client) Tracks(ctx context.Context, trackIDs []string) ([]Track, error) {
+	var tracks []Track
+	for start := 0; start < len(trackIDs); start += maxTracksPerRequest {
+		end := start + maxTracksPerRequest
+		if end > len(trackIDs) {
+			end = len(trackIDs)
+		}
+
+		var resp struct {
+			Tracks []*Track `json:"tracks"`
+		}
+		query := url.Values{"ids": {strings.Join(trackIDs[start:end], ",")}}
+		err := c.do(ctx, http.MethodGet, "/tracks", query, nil, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, track := range resp.Tracks {
+			if track != nil {
+				tracks = append(tracks, *track)
+			}
+		}
+	}
+	return tracks, nil
+}
+
+// Album returns an album's details
+func (c *Client) Album(ctx context.Context, albumID string) (*Album, error) {
+	var album Album
+	err := c.do(ctx, http.MethodGet, "/albums/"+url.PathEscape(albumID), nil, nil, &album)
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// AlbumTracks returns up to limit tracks of an album in track order, following pagination.
+// The album endpoint leaves out each track's album, so callers that need it should fill it in.
+func (c *Client) AlbumTracks(ctx context.Context, albumID string, limit int) ([]Track, error) {
+	var tracks []Track
+	err := c.paginate(ctx, "/albums/"+url.PathEscape(albumID)+"/tracks", limit, func(data []byte) (int, string, error) {
+		var p page[Track]
+		if err := json.Unmarshal(data, &p); err != nil {
+			return 0, "", err
+		}
+		tracks = append(tracks, p.Items...)
+		return len(p.Items), p.Next, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	return tracks, nil
+}
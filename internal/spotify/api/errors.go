@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by *Error through errors.Is
+var (
+	// ErrUnauthorized means the access token is missing, expired or revoked (401)
+	ErrUnauthorized = errors.New("spotify: unauthorized")
+	// ErrPremiumRequired means the user needs Spotify Premium for player control (403)
+	ErrPremiumRequired = errors.New("spotify: premium required")
+	// ErrNoActiveDevice means there is no device to control (404)
+	ErrNoActiveDevice = errors.New("spotify: no active device")
+	// ErrNotFound means the requested track, album or playlist doesn't exist or isn't visible to the user (404)
+	ErrNotFound = errors.New("spotify: not found")
+	// ErrRateLimited means the app sent too many requests (429)
+	ErrRateLimited = errors.New("spotify: rate limited")
+)
+
+// Error is a non-2xx response from the Spotify Web API
+type Error struct {
+	Status     int           // HTTP status code
+	Message    string        // Spotify's error message
+	Reason     string        // Spotify's player error reason, e.g. PREMIUM_REQUIRED
+	RetryAfter time.Duration // From the Retry-After header, if present
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("spotify: status %d", e.Status)
+	}
+	return fmt.Sprintf("spotify: status %d: %s", e.Status, e.Message)
+}
+
+// Is lets errors.Is match an *Error against the sentinel errors
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.Status == http.StatusUnauthorized
+	case ErrPremiumRequired:
+		return e.Status == http.StatusForbidden &&
+			(e.Reason == "PREMIUM_REQUIRED" || strings.Contains(strings.ToLower(e.Message), "premium"))
+	case ErrNoActiveDevice:
+		return e.Status == http.StatusNotFound &&
+			(e.Reason == "NO_ACTIVE_DEVICE" || strings.Contains(strings.ToLower(e.Message), "device"))
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound && !e.Is(ErrNoActiveDevice)
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Temporary reports whether retrying the request later may succeed
+func (e *Error) Temporary() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= 500
+}
+
+// newError builds an *Error from a failed response
+func newError(resp *http.Response) *Error {
+	apiErr := &Error{
+		Status:     resp.StatusCode,
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+			Reason  string `json:"reason"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Error.Message != "" {
+		apiErr.Message = payload.Error.Message
+		apiErr.Reason = payload.Error.Reason
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	return apiErr
+}
+
+// ParseRetryAfter parses a Retry-After header given in seconds or as an HTTP date; it returns 0 if absent or invalid
+func ParseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Repeat modes accepted by SetRepeat
+const (
+	RepeatOff     = "off"
+	RepeatTrack   = "track"
+	RepeatContext = "context"
+)
+
+// PlayOptions describes what Play should start
+type PlayOptions struct {
+	URIs       []string `json:"uris,omitempty"`
+	ContextURI string   `json:"context_uri,omitempty"`
+	PositionMs int      `json:"position_ms,omitempty"`
+}
+
+// deviceQuery returns the query selecting a device, or nil for the active device
+func deviceQuery(deviceID string) url.Values {
+	if deviceID == "" {
+		return nil
+	}
+	return url.Values{"device_id": {deviceID}}
+}
+
+// PlayerState returns the user's current playback, or nil if nothing is playing on any device
+func (c *Client) PlayerState(ctx context.Context) (*PlayerState, error) {
+	// A 204 leaves state nil
+	var state *PlayerState
+	err := c.do(ctx, http.MethodGet, "/me/player", nil, nil, &state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Devices returns the user's available devices
+func (c *Client) Devices(ctx context.Context) ([]Device, error) {
+	var resp struct {
+		Devices []Device `json:"devices"`
+	}
+
+	err := c.do(ctx, http.MethodGet, "/me/player/devices", nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Devices, nil
+}
+
+// Play starts or resumes playback on a device; an empty deviceID targets the active device
+func (c *Client) Play(ctx context.Context, deviceID string, opts PlayOptions) error {
+	var body interface{}
+	if len(opts.URIs) > 0 || opts.ContextURI != "" || opts.PositionMs > 0 {
+		body = opts
+	}
+	return c.do(ctx, http.MethodPut, "/me/player/play", deviceQuery(deviceID), body, nil)
+}
+
+// Pause pauses playback on a device
+func (c *Client) Pause(ctx context.Context, deviceID string) error {
+	return c.do(ctx, http.MethodPut, "/me/player/pause", deviceQuery(deviceID), nil, nil)
+}
+
+// Seek moves playback on a device to a position in the current track
+func (c *Client) Seek(ctx context.Context, deviceID string, positionMs int) error {
+	query := url.Values{"position_ms": {strconv.Itoa(positionMs)}}
+	if deviceID != "" {
+		query.Set("device_id", deviceID)
+	}
+	return c.do(ctx, http.MethodPut, "/me/player/seek", query, nil, nil)
+}
+
+// SetVolume sets the volume of a device, from 0 to 100
+func (c *Client) SetVolume(ctx context.Context, deviceID string, percent int) error {
+	query := url.Values{"volume_percent": {strconv.Itoa(percent)}}
+	if deviceID != "" {
+		query.Set("device_id", deviceID)
+	}
+	return c.do(ctx, http.MethodPut, "/me/player/volume", query, nil, nil)
+}
+
+// SetShuffle turns shuffle on or off for a device
+func (c *Client) SetShuffle(ctx context.Context, deviceID string, shuffle bool) error {
+	query := url.Values{"state": {strconv.FormatBool(shuffle)}}
+	if deviceID != "" {
+		query.Set("device_id", deviceID)
+	}
+	return c.do(ctx, http.MethodPut, "/me/player/shuffle", query, nil, nil)
+}
+
+// SetRepeat sets the repeat mode of a device to RepeatOff, RepeatTrack or RepeatContext
+func (c *Client) SetRepeat(ctx context.Context, deviceID, mode string) error {
+	query := url.Values{"state": {mode}}
+	if deviceID != "" {
+		query.Set("device_id", deviceID)
+	}
+	return c.do(ctx, http.MethodPut, "/me/player/repeat", query, nil, nil)
+}
+
+// Queue returns the user's playback queue
+func (c *Client) Queue(ctx context.Context) (*Queue, error) {
+	var queue Queue
+	err := c.do(ctx, http.MethodGet, "/me/player/queue", nil, nil, &queue)
+	if err != nil {
+		return nil, err
+	}
+	return &queue, nil
+}
+
+// AddToQueue appends a track to the user's playback queue on a device
+func (c *Client) AddToQueue(ctx context.Context, deviceID, uri string) error {
+	query := url.Values{"uri": {uri}}
+	if deviceID != "" {
+		query.Set("device_id", deviceID)
+	}
+	return c.do(ctx, http.MethodPost, "/me/player/queue", query, nil, nil)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxPageSize is the largest page the listing endpoints accept
+const maxPageSize = 50
+
+// Playlist returns a playlist's details
+func (c *Client) Playlist(ctx context.Context, playlistID string) (*Playlist, error) {
+	var playlist Playlist
+	err := c.do(ctx, http.MethodGet, "/playlists/"+url.PathEscape(playlistID), nil, nil, &playlist)
+	if err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+// PlaylistTracks returns up to limit tracks of a playlist, following pagination; local files and episodes are skipped
+func (c *Client) PlaylistTracks(ctx context.Context, playlistID string, limit int) ([]Track, error) {
+	var tracks []Track
+	err := c.paginate(ctx, "/playlists/"+url.PathEscape(playlistID)+"/tracks", limit, func(data []byte) (int, string, error) {
+		var p page[struct {
+			IsLocal bool   `json:"is_local"`
+			Track   *Track `json:"track"`
+		}]
+		if err := json.Unmarshal(data, &p); err != nil {
+			return 0, "", err
+		}
+		for _, item := range p.Items {
+			if item.IsLocal || item.Track == nil || !strings.HasPrefix(item.Track.URI, "spotify:track:") {
+				continue
+			}
+			tracks = append(tracks, *item.Track)
+		}
+		return len(p.Items), p.Next, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	return tracks, nil
+}
+
+// MyPlaylists returns up to limit of the current user's playlists
+func (c *Client) MyPlaylists(ctx context.Context, limit int) ([]Playlist, error) {
+	var playlists []Playlist
+	err := c.paginate(ctx, "/me/playlists", limit, func(data []byte) (int, string, error) {
+		var p page[Playlist]
+		if err := json.Unmarshal(data, &p); err != nil {
+			return 0, "", err
+		}
+		playlists = append(playlists, p.Items...)
+		return len(p.Items), p.Next, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(playlists) > limit {
+		playlists = playlists[:limit]
+	}
+	return playlists, nil
+}
+
+// paginate walks a paginated listing until limit items have been seen (0 means all) or there are no more pages.
+// handle decodes one raw page and returns how many items it held and the next page URL.
+func (c *Client) paginate(ctx context.Context, path string, limit int, handle func(data []byte) (int, string, error)) error {
+	seen := 0
+	offset := 0
+	for {
+		pageSize := maxPageSize
+		if limit > 0 && limit-seen < pageSize {
+			pageSize = limit - seen
+		}
+
+		query := url.Values{
+			"limit":  {strconv.Itoa(pageSize)},
+			"offset": {strconv.Itoa(offset)},
+		}
+
+		var raw json.RawMessage
+		err := c.do(ctx, http.MethodGet, path, query, nil, &raw)
+		if err != nil {
+			return err
+		}
+
+		count, next, err := handle(raw)
+		if err != nil {
+			return err
+		}
+
+		seen += count
+		offset += count
+		if next == "" || count == 0 || (limit > 0 && seen >= limit) {
+			return nil
+		}
+	}
+}
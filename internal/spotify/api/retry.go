@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Retry defaults used by NewTransport
+const (
+	DefaultMaxRetries    = 3
+	DefaultMaxRetryAfter = 30 * time.Second
+	baseRetryDelay       = 500 * time.Millisecond
+	maxRetryDelay        = 8 * time.Second
+)
+
+// Transport is an http.RoundTripper that paces requests through a shared Limiter and retries
+// rate limited (429) and transient (5xx, network) failures.
+type Transport struct {
+	Base    http.RoundTripper // Defaults to http.DefaultTransport
+	Limiter *Limiter          // Optional; shared by every client of the app
+	// MaxRetries is how many times a request is retried after the first attempt
+	MaxRetries int
+	// MaxRetryAfter is the longest Retry-After the transport waits out; longer waits are returned to the caller
+	MaxRetryAfter time.Duration
+}
+
+// NewTransport creates a retrying transport on top of base that shares limiter
+func NewTransport(base http.RoundTripper, limiter *Limiter) *Transport {
+	return &Transport{
+		Base:          base,
+		Limiter:       limiter,
+		MaxRetries:    DefaultMaxRetries,
+		MaxRetryAfter: DefaultMaxRetryAfter,
+	}
+}
+
+// RoundTrip sends the request, waiting for the limiter and retrying where it is safe to
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if t.Limiter != nil {
+			if err := t.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		attemptReq, err := t.rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base().RoundTrip(attemptReq)
+
+		wait, retry := t.retryDelay(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		if resp != nil {
+			// Drain so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		err = sleep(ctx, wait)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay decides whether an attempt should be retried and how long to wait first
+func (t *Transport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= t.MaxRetries || req.Context().Err() != nil {
+		return 0, false
+	}
+
+	// Retrying needs a fresh copy of the body
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+
+	if err != nil {
+		return backoff(attempt), idempotent(req.Method)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// A 429 means the request was not processed, so any method may be retried
+		wait := ParseRetryAfter(resp.Header.Get("Retry-After"))
+		if wait == 0 {
+			wait = backoff(attempt)
+		}
+		if t.Limiter != nil {
+			t.Limiter.PauseUntil(time.Now().Add(wait))
+		}
+		if t.MaxRetryAfter > 0 && wait > t.MaxRetryAfter {
+			return 0, false
+		}
+		return wait, true
+	case resp.StatusCode >= 500:
+		// The request may have been processed; only repeat it if that is harmless
+		return backoff(attempt), idempotent(req.Method)
+	}
+
+	return 0, false
+}
+
+// rewind returns the request to send for an attempt, with a fresh body for retries
+func (t *Transport) rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+
+	retryReq := req.Clone(req.Context())
+	retryReq.Body = body
+	return retryReq, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// backoff returns a jittered exponential delay for the given attempt
+func backoff(attempt int) time.Duration {
+	delay := baseRetryDelay << attempt
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	// Full jitter over the upper half keeps retries from many sessions apart
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// idempotent reports whether repeating a request with this method is harmless
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
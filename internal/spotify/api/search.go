@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// SearchTracks returns up to limit tracks matching a free-text query
+func (c *Client) SearchTracks(ctx context.Context, query string, limit int) ([]Track, error) {
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("type", "track")
+	params.Add("limit", strconv.Itoa(limit))
+
+	var resp struct {
+		Tracks page[Track] `json:"tracks"`
+	}
+
+	err := c.do(ctx, http.MethodGet, "/search", params, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tracks.Items, nil
+}
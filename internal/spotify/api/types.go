@@ -0,0 +1,89 @@
+package api
+
+// Artist is a simplified Spotify artist
+type Artist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+}
+
+// Image is a piece of cover art
+type Image struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Album is a simplified Spotify album
+type Album struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	URI     string   `json:"uri"`
+	Images  []Image  `json:"images"`
+	Artists []Artist `json:"artists"`
+}
+
+// ExternalURLs holds links to the object on the Spotify web player
+type ExternalURLs struct {
+	Spotify string `json:"spotify"`
+}
+
+// Track is a Spotify track
+type Track struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	URI          string       `json:"uri"`
+	DurationMs   int          `json:"duration_ms"`
+	Explicit     bool         `json:"explicit"`
+	Popularity   int          `json:"popularity"`
+	IsPlayable   *bool        `json:"is_playable,omitempty"`
+	Artists      []Artist     `json:"artists"`
+	Album        Album        `json:"album"`
+	ExternalURLs ExternalURLs `json:"external_urls"`
+}
+
+// Device is a Spotify Connect device
+type Device struct {
+	ID            string `json:"id"`
+	IsActive      bool   `json:"is_active"`
+	IsRestricted  bool   `json:"is_restricted"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	VolumePercent int    `json:"volume_percent"`
+}
+
+// PlayerState is the user's current playback, as returned by GET /me/player
+type PlayerState struct {
+	Device       *Device `json:"device"`
+	RepeatState  string  `json:"repeat_state"`
+	ShuffleState bool    `json:"shuffle_state"`
+	Timestamp    int64   `json:"timestamp"` // Unix milliseconds when the state was sampled
+	ProgressMs   int     `json:"progress_ms"`
+	IsPlaying    bool    `json:"is_playing"`
+	Item         *Track  `json:"item"`
+}
+
+// Queue is the user's playback queue, as returned by GET /me/player/queue
+type Queue struct {
+	CurrentlyPlaying *Track  `json:"currently_playing"`
+	Queue            []Track `json:"queue"`
+}
+
+// Playlist is a simplified Spotify playlist
+type Playlist struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	URI          string       `json:"uri"`
+	Images       []Image      `json:"images"`
+	ExternalURLs ExternalURLs `json:"external_urls"`
+	Tracks       struct {
+		Total int `json:"total"`
+	} `json:"tracks"`
+}
+
+// page is one page of a paginated Spotify listing
+type page[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next"`
+	Total int    `json:"total"`
+}
@@ -0,0 +1,86 @@
+package spotify
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SessionClock is the authoritative playback position of a session, kept with millisecond precision.
+// The position is stored as of UpdatedAtMs and advances with wall-clock time while the clock runs.
+type SessionClock struct {
+	PositionMs  int   `json:"position_ms"`   // Position when the clock was last changed
+	IsPlaying   bool  `json:"is_playing"`    // Whether the position advances
+	UpdatedAtMs int64 `json:"updated_at_ms"` // Unix milliseconds when the clock was last changed
+}
+
+// UpdatedAt returns when the clock was last changed
+func (c SessionClock) UpdatedAt() time.Time {
+	return time.UnixMilli(c.UpdatedAtMs)
+}
+
+// PositionAt returns the playback position at the given time
+func (c SessionClock) PositionAt(now time.Time) int {
+	if !c.IsPlaying {
+		return c.PositionMs
+	}
+
+	elapsed := now.UnixMilli() - c.UpdatedAtMs
+	if elapsed < 0 {
+		// Another instance with a slightly fast clock wrote the state
+		elapsed = 0
+	}
+	return c.PositionMs + int(elapsed)
+}
+
+// Play resumes the clock from its current position
+func (c *SessionClock) Play(now time.Time) {
+	c.PositionMs = c.PositionAt(now)
+	c.IsPlaying = true
+	c.UpdatedAtMs = now.UnixMilli()
+}
+
+// Pause stops the clock at its current position
+func (c *SessionClock) Pause(now time.Time) {
+	c.PositionMs = c.PositionAt(now)
+	c.IsPlaying = false
+	c.UpdatedAtMs = now.UnixMilli()
+}
+
+// Seek moves the clock to a position, keeping it running or paused
+func (c *SessionClock) Seek(positionMs int, now time.Time) {
+	if positionMs < 0 {
+		positionMs = 0
+	}
+	c.PositionMs = positionMs
+	c.UpdatedAtMs = now.UnixMilli()
+}
+
+// Reset stops the clock at the start of a song
+func (c *SessionClock) Reset(now time.Time) {
+	c.PositionMs = 0
+	c.IsPlaying = false
+	c.UpdatedAtMs = now.UnixMilli()
+}
+
+// UnmarshalJSON decodes a playback state, migrating sessions saved with the old
+// last_updated_at field, which held Unix seconds. They are saved in the new format
+// the next time they are updated.
+func (p *PlaybackState) UnmarshalJSON(data []byte) error {
+	// The alias has no methods, which avoids recursing into this one
+	type playbackState PlaybackState
+	var decoded struct {
+		playbackState
+		LegacyUpdatedAt int64 `json:"last_updated_at"`
+	}
+
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	*p = PlaybackState(decoded.playbackState)
+	if p.UpdatedAtMs == 0 && decoded.LegacyUpdatedAt != 0 {
+		p.UpdatedAtMs = decoded.LegacyUpdatedAt * 1000
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"jam-bot/internal/spotify/api"
+)
+
+// ErrDeviceNotFound is returned when no device matches the name a user asked for
+var ErrDeviceNotFound = errors.New("no Spotify device with that name")
+
+// ErrAmbiguousDevice is returned when several devices match the name a user asked for
+var ErrAmbiguousDevice = errors.New("more than one Spotify device matches that name")
+
+// errRestrictedDevices is reported when a user's only devices can't be controlled through the API
+var errRestrictedDevices = errors.New("all Spotify devices are restricted")
+
+// unknownDeviceRank places device types missing from fallbackDeviceRank before smartphones
+const unknownDeviceRank = 5
+
+// fallbackDeviceRank orders device types when a user has no preferred or active device; lower ranks are tried first
+var fallbackDeviceRank = map[string]int{
+	"computer":   0,
+	"speaker":    1,
+	"tv":         2,
+	"castaudio":  3,
+	"castvideo":  3,
+	"avr":        4,
+	"stb":        4,
+	"smartphone": 6, // Most likely to be in someone's pocket
+}
+
+// selectDevice picks the device to play on: the preferred device, then the active device,
+// then the most likely listening device by type. Restricted devices are never picked.
+func selectDevice(devices []api.Device, preferred *DevicePreference) (api.Device, error) {
+	var usable []api.Device
+	for _, device := range devices {
+		if !device.IsRestricted {
+			usable = append(usable, device)
+		}
+	}
+
+	if len(usable) == 0 {
+		if len(devices) > 0 {
+			return api.Device{}, errRestrictedDevices
+		}
+		return api.Device{}, errNoDevices
+	}
+
+	if preferred != nil {
+		// Match by ID first; some clients get a new ID on restart, so fall back to the name
+		for _, device := range usable {
+			if device.ID == preferred.ID {
+				return device, nil
+			}
+		}
+		for _, device := range usable {
+			if strings.EqualFold(device.Name, preferred.Name) {
+				return device, nil
+			}
+		}
+	}
+
+	for _, device := range usable {
+		if device.IsActive {
+			return device, nil
+		}
+	}
+
+	best := usable[0]
+	for _, device := range usable[1:] {
+		if deviceRank(device) < deviceRank(best) {
+			best = device
+		}
+	}
+	return best, nil
+}
+
+// deviceRank returns a device's place in the fallback order
+func deviceRank(device api.Device) int {
+	rank, ok := fallbackDeviceRank[strings.ToLower(device.Type)]
+	if !ok {
+		return unknownDeviceRank
+	}
+	return rank
+}
+
+// findDevice matches a device by exact name, then by a unique partial name, ignoring case
+func findDevice(devices []api.Device, name string) (api.Device, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	for _, device := range devices {
+		if strings.ToLower(device.Name) == name {
+			return device, nil
+		}
+	}
+
+	var matches []api.Device
+	for _, device := range devices {
+		if strings.Contains(strings.ToLower(device.Name), name) {
+			matches = append(matches, device)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return api.Device{}, ErrDeviceNotFound
+	case 1:
+		return matches[0], nil
+	default:
+		return api.Device{}, ErrAmbiguousDevice
+	}
+}
+
+// playbackDevice returns the device a participant's playback should go to
+func (s *Service) playbackDevice(ctx context.Context, userID string, client *api.Client) (api.Device, error) {
+	devices, err := client.Devices(ctx)
+	if err != nil {
+		return api.Device{}, err
+	}
+
+	preferred, err := s.PreferredDevice(ctx, userID)
+	if err != nil {
+		// Playback can still go ahead on the active device
+		log.Printf("[WARN] Failed to load preferred device for user %s: %v", userID, err)
+	}
+
+	return selectDevice(devices, preferred)
+}
+
+// GetUserDevices retrieves the available devices for a user
+func (s *Service) GetUserDevices(ctx context.Context, discordUserID string) ([]api.Device, error) {
+	client, err := s.apiClient(ctx, discordUserID)
+	if err != nil {
+		return nil, err
+	}
+	return client.Devices(ctx)
+}
+
+// PreferredDevice returns the device a user chose, or nil if they haven't chosen one
+func (s *Service) PreferredDevice(ctx context.Context, discordUserID string) (*DevicePreference, error) {
+	preferred, err := s.preferences.GetPreferredDevice(ctx, discordUserID)
+	if errors.Is(err, ErrNoPreferredDevice) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return preferred, nil
+}
+
+// SetPreferredDevice remembers the user's device matching name as the one to play jam sessions on
+func (s *Service) SetPreferredDevice(ctx context.Context, discordUserID, name string) (api.Device, error) {
+	devices, err := s.GetUserDevices(ctx, discordUserID)
+	if err != nil {
+		return api.Device{}, fmt.Errorf("failed to get devices: %w", err)
+	}
+
+	device, err := findDevice(devices, name)
+	if err != nil {
+		return api.Device{}, err
+	}
+
+	if device.IsRestricted {
+		return api.Device{}, fmt.Errorf("%s can't be controlled by the bot", device.Name)
+	}
+
+	err = s.preferences.SetPreferredDevice(ctx, discordUserID, DevicePreference{ID: device.ID, Name: device.Name})
+	if err != nil {
+		return api.Device{}, fmt.Errorf("failed to save preferred device: %w", err)
+	}
+
+	return device, nil
+}
+
+// ClearPreferredDevice goes back to playing on whichever device is active
+func (s *Service) ClearPreferredDevice(ctx context.Context, discordUserID string) error {
+	return s.preferences.ClearPreferredDevice(ctx, discordUserID)
+}
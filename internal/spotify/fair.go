@@ -0,0 +1,140 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// maxFairWeight caps how many songs a requester can get per round of the fair queue
+const maxFairWeight = 5
+
+// ErrInvalidFairWeight is returned for fair queue weights outside 1 to maxFairWeight
+var ErrInvalidFairWeight = fmt.Errorf("weight must be between 1 and %d", maxFairWeight)
+
+// The fair queue gives every requester a sub-queue of their own songs and plays the sub-queues
+// in rounds. In each round a requester gets as many songs as their weight (1 unless set), so
+// someone who adds twenty songs in a row can't hold up everyone else. The functions below work
+// on plain song slices so the ordering can be reasoned about apart from sessions and storage.
+
+// fairWeight returns a requester's songs per round
+func fairWeight(weights map[string]int, requester string) int {
+	if weight, ok := weights[requester]; ok && weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+// fairRounds returns the round each upcoming song falls in. A requester's nth song is in round
+// n/weight, counting the playing song as their first so they don't also go first next round.
+func fairRounds(current *Song, upcoming []Song, weights map[string]int) []int {
+	counts := make(map[string]int)
+	if current != nil {
+		counts[current.AddedBy]++
+	}
+
+	rounds := make([]int, len(upcoming))
+	for i, song := range upcoming {
+		rounds[i] = counts[song.AddedBy] / fairWeight(weights, song.AddedBy)
+		counts[song.AddedBy]++
+	}
+	return rounds
+}
+
+// fairOrder interleaves the requesters' sub-queues round by round. Each sub-queue keeps the order
+// its songs have in upcoming, and within a round requesters keep the order they appear in upcoming.
+func fairOrder(current *Song, upcoming []Song, weights map[string]int) []Song {
+	rounds := fairRounds(current, upcoming, weights)
+
+	order := make([]int, len(upcoming))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rounds[order[a]] < rounds[order[b]]
+	})
+
+	ordered := make([]Song, len(upcoming))
+	for i, index := range order {
+		ordered[i] = upcoming[index]
+	}
+	return ordered
+}
+
+// fairInsertIndex returns where a newly added song goes among the upcoming songs: at the end of
+// the round it falls in. Songs moved by hand keep their place.
+func fairInsertIndex(current *Song, upcoming []Song, song Song, weights map[string]int) int {
+	rounds := fairRounds(current, upcoming, weights)
+
+	count := 0
+	if current != nil && current.AddedBy == song.AddedBy {
+		count++
+	}
+	for _, queued := range upcoming {
+		if queued.AddedBy == song.AddedBy {
+			count++
+		}
+	}
+	round := count / fairWeight(weights, song.AddedBy)
+
+	for i, queuedRound := range rounds {
+		if queuedRound > round {
+			return i
+		}
+	}
+	return len(upcoming)
+}
+
+// currentSong returns the playing song at the head of the queue, or nil if nothing is playing
+func (session *Session) currentSong() *Song {
+	if !session.hasCurrent() {
+		return nil
+	}
+	return &session.Queue[0]
+}
+
+// SetFairQueue turns fair-share ordering of the upcoming songs on or off
+func (s *Service) SetFairQueue(ctx context.Context, channelID string, enabled bool) (*Session, error) {
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		session.Fair = enabled
+		session.reorderUpcoming()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyPlaybackChange(session)
+
+	return session, nil
+}
+
+// SetFairWeight sets how many songs a requester gets per round of the fair queue
+func (s *Service) SetFairWeight(ctx context.Context, channelID, userID string, weight int) error {
+	if weight < 1 || weight > maxFairWeight {
+		return ErrInvalidFairWeight
+	}
+
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		if weight == 1 {
+			delete(session.FairWeights, userID)
+		} else {
+			if session.FairWeights == nil {
+				session.FairWeights = make(map[string]int)
+			}
+			session.FairWeights[userID] = weight
+		}
+
+		if session.Fair {
+			session.reorderUpcoming()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyPlaybackChange(session)
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package spotify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fairSongs builds songs from IDs like "a1", whose first letter is the requester
+func fairSongs(ids string) []Song {
+	songs := []Song{}
+	for _, id := range strings.Fields(ids) {
+		songs = append(songs, Song{URI: "spotify:track:" + id, Title: id, AddedBy: id[:1]})
+	}
+	return songs
+}
+
+// fairIDs returns the IDs fairSongs built songs from
+func fairIDs(songs []Song) string {
+	ids := make([]string, len(songs))
+	for i, song := range songs {
+		ids[i] = song.Title
+	}
+	return strings.Join(ids, " ")
+}
+
+// fairCurrent returns the playing song for an ID, or nil for none
+func fairCurrent(id string) *Song {
+	if id == "" {
+		return nil
+	}
+	return &fairSongs(id)[0]
+}
+
+func TestFairRounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		upcoming string
+		weights  map[string]int
+		want     []int
+	}{
+		{name: "empty", upcoming: "", want: []int{}},
+		{name: "no current song", upcoming: "a1 a2 b1 a3 b2", want: []int{0, 1, 0, 2, 1}},
+		{name: "current song counts as a turn", current: "a0", upcoming: "a1 a2 b1 a3 b2", want: []int{1, 2, 0, 3, 1}},
+		{name: "weighted", upcoming: "a1 a2 b1 a3 b2", weights: map[string]int{"a": 2}, want: []int{0, 0, 0, 1, 1}},
+		{name: "weighted with current song", current: "a0", upcoming: "a1 a2 b1 a3 b2", weights: map[string]int{"a": 2}, want: []int{0, 1, 0, 1, 1}},
+		{name: "zero weight counts as one", upcoming: "a1 a2", weights: map[string]int{"a": 0}, want: []int{0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fairRounds(fairCurrent(tt.current), fairSongs(tt.upcoming), tt.weights)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fairRounds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFairOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		upcoming string
+		weights  map[string]int
+		want     string
+	}{
+		{name: "empty", upcoming: "", want: ""},
+		{name: "no current song", upcoming: "a1 a2 b1 a3 b2", want: "a1 b1 a2 b2 a3"},
+		{name: "current song goes first", current: "a0", upcoming: "a1 a2 b1 a3 b2", want: "b1 a1 b2 a2 a3"},
+		{name: "three requesters", upcoming: "a1 a2 a3 b1 c1", want: "a1 b1 c1 a2 a3"},
+		{name: "weighted", upcoming: "a1 a2 b1 a3 b2", weights: map[string]int{"a": 2}, want: "a1 a2 b1 a3 b2"},
+		{name: "weighted with current song", current: "a0", upcoming: "a1 a2 b1 a3 b2", weights: map[string]int{"a": 2}, want: "a1 b1 a2 a3 b2"},
+		{name: "already fair", upcoming: "a1 b1 a2 b2", want: "a1 b1 a2 b2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fairIDs(fairOrder(fairCurrent(tt.current), fairSongs(tt.upcoming), tt.weights))
+			if got != tt.want {
+				t.Errorf("fairOrder() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFairInsertIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		upcoming string
+		song     string
+		weights  map[string]int
+		want     int
+	}{
+		{name: "empty queue", upcoming: "", song: "a1", want: 0},
+		{name: "end of the last round", upcoming: "a1 b1 a2", song: "b2", want: 3},
+		{name: "first round", upcoming: "a1 a2 a3", song: "b1", want: 1},
+		{name: "current song counts as a turn", current: "b0", upcoming: "a1 a2", song: "b1", want: 2},
+		{name: "other requester with current song", current: "a0", upcoming: "b1 a1", song: "c1", want: 1},
+		{name: "weighted requester", upcoming: "a1 a2 b1 a3", song: "a4", weights: map[string]int{"a": 2}, want: 4},
+		{name: "new requester among weighted", upcoming: "a1 a2 b1 a3", song: "c1", weights: map[string]int{"a": 2}, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			song := fairSongs(tt.song)[0]
+			got := fairInsertIndex(fairCurrent(tt.current), fairSongs(tt.upcoming), song, tt.weights)
+			if got != tt.want {
+				t.Errorf("fairInsertIndex() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFairEnqueueMatchesReorder checks that adding songs one at a time gives the same order as
+// sorting the whole queue again, which is what turning the fair queue on does
+func TestFairEnqueueMatchesReorder(t *testing.T) {
+	tests := []struct {
+		name    string
+		playing bool
+		songs   string
+		weights map[string]int
+	}{
+		{name: "nothing playing", songs: "a1 a2 a3 b1 c1 b2 a4 c2 c3"},
+		{name: "head playing", playing: true, songs: "a1 a2 a3 b1 c1 b2 a4 c2 c3"},
+		{name: "weighted", songs: "a1 a2 a3 b1 c1 b2 a4 c2 c3 b3", weights: map[string]int{"a": 2, "c": 3}},
+		{name: "weighted with head playing", playing: true, songs: "a1 a2 a3 b1 c1 b2 a4 c2 c3 b3", weights: map[string]int{"a": 2, "c": 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{Queue: []Song{}, Fair: true, FairWeights: tt.weights}
+			for i, song := range fairSongs(tt.songs) {
+				session.enqueue(song)
+				if i == 0 && tt.playing {
+					session.Playback.CurrentSong = session.Queue[0]
+					session.Playback.IsPlaying = true
+				}
+			}
+			enqueued := fairIDs(session.Queue)
+
+			// Scramble the songs that may move, then let reorderUpcoming sort them back
+			start := session.firstMovable()
+			upcoming := session.Queue[start:]
+			for i, j := 0, len(upcoming)-1; i < j; i, j = i+1, j-1 {
+				upcoming[i], upcoming[j] = upcoming[j], upcoming[i]
+			}
+			session.reorderUpcoming()
+
+			if got := fairIDs(session.Queue); got != enqueued {
+				t.Errorf("reorderUpcoming() = %q, enqueue gave %q", got, enqueued)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"jam-bot/internal/spotify/api"
+)
+
+// maxFanOutWorkers bounds how many participants are contacted at once
+const maxFanOutWorkers = 8
+
+// fanOutTimeout is the deadline shared by every participant of one fan-out
+const fanOutTimeout = 15 * time.Second
+
+// errNoDevices is reported for participants without any Spotify device open
+var errNoDevices = errors.New("no Spotify devices found")
+
+// ParticipantResult is the outcome of a playback change for one participant
+type ParticipantResult struct {
+	UserID string
+	Device string // Name of the device that was controlled, if one was found
+	Err    error
+}
+
+// Problem describes the failure in words the participant can act on; it is empty on success
+func (r ParticipantResult) Problem() string {
+	if r.Err == nil {
+		return ""
+	}
+	return describeAPIError(r.Err)
+}
+
+// PlaybackReport summarizes a playback change across a session's participants
+type PlaybackReport struct {
+	Song       Song                // The song the change applied to
+	PositionMs int                 // Where the song was started or paused
+	Results    []ParticipantResult // One per participant, in session order
+	Finished   bool                // The skipped song was the last one, so playback stopped
+	Paused     bool                // Playback was paused, so the next song was left waiting instead of started
+}
+
+// Succeeded returns how many participants the change reached
+func (r PlaybackReport) Succeeded() int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Err == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns the participants the change did not reach
+func (r PlaybackReport) Failed() []ParticipantResult {
+	var failed []ParticipantResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// participantAction changes playback on one participant's device
+type participantAction func(ctx context.Context, client *api.Client, device api.Device) error
+
+// clientAction does one participant's part of a fan-out and returns the name of the device it used
+type clientAction func(ctx context.Context, userID string, client *api.Client) (string, error)
+
+// fanOut runs action for every participant on the device their playback should go to
+func (s *Service) fanOut(ctx context.Context, participants []string, action participantAction) []ParticipantResult {
+	return s.fanOutClients(ctx, participants, func(ctx context.Context, userID string, client *api.Client) (string, error) {
+		device, err := s.playbackDevice(ctx, userID, client)
+		if err != nil {
+			return "", err
+		}
+		return device.Name, action(ctx, client, device)
+	})
+}
+
+// fanOutClients runs action for every participant using a bounded pool of workers and one deadline
+// for the whole batch, so a slow participant can't hold up the rest for long.
+func (s *Service) fanOutClients(ctx context.Context, participants []string, action clientAction) []ParticipantResult {
+	ctx, cancel := context.WithTimeout(ctx, fanOutTimeout)
+	defer cancel()
+
+	results := make([]ParticipantResult, len(participants))
+	jobs := make(chan int)
+
+	workers := maxFanOutWorkers
+	if len(participants) < workers {
+		workers = len(participants)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = s.runParticipant(ctx, participants[i], action)
+			}
+		}()
+	}
+
+	for i := range participants {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// runParticipant runs action with a participant's API client
+func (s *Service) runParticipant(ctx context.Context, userID string, action clientAction) ParticipantResult {
+	result := ParticipantResult{UserID: userID}
+
+	// The deadline may have passed while this participant waited for a worker
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	client, err := s.apiClient(ctx, userID)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Device, result.Err = action(ctx, userID, client)
+	return result
+}
+
+// notifyFailures DMs each participant the change did not reach, so they know how to fix it
+func (s *Service) notifyFailures(results []ParticipantResult, action string) {
+	if s.SendDM == nil {
+		return
+	}
+
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		err := s.SendDM(result.UserID, fmt.Sprintf("❌ Failed to %s: %s", action, result.Problem()))
+		if err != nil {
+			log.Printf("[ERROR] Failed to send DM to user %s: %v", result.UserID, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package spotify
+
+import (
+	"context"
+	"time"
+
+	"jam-bot/internal/spotify/api"
+)
+
+// SessionSettings are the play modes a jam session starts with
+type SessionSettings struct {
+	Shuffle bool
+	Repeat  RepeatMode
+	Fair    bool
+}
+
+// CreatedAt returns when the session was started, or the zero time for sessions started before this was recorded
+func (session *Session) CreatedAt() time.Time {
+	if session.CreatedAtMs == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(session.CreatedAtMs)
+}
+
+// StartSession starts a jam session in a channel with hostID as its host and first participant.
+// It returns an error wrapping ErrSessionExists if the channel already has a session.
+func (s *Service) StartSession(ctx context.Context, guildID, channelID, hostID string, settings SessionSettings) (*Session, error) {
+	now := time.Now()
+	session := Session{
+		ChannelID:    channelID,
+		GuildID:      guildID,
+		Host:         hostID,
+		CreatedAtMs:  now.UnixMilli(),
+		Participants: []string{hostID},
+		Queue:        []Song{},
+		Shuffle:      settings.Shuffle,
+		Repeat:       settings.Repeat,
+		Fair:         settings.Fair,
+	}
+	if settings.Shuffle {
+		session.ShuffleSeed = newShuffleSeed()
+	}
+	session.Playback.Reset(now)
+
+	err := s.sessions.CreateSession(ctx, &session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// EndSession pauses every participant still listening, deletes the channel's session and returns
+// the session as it was when it ended
+func (s *Service) EndSession(ctx context.Context, channelID string) (*Session, error) {
+	// The store reads and deletes the session in one step, so no concurrent change lands in between
+	session, err := s.sessions.DeleteSession(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing may restart playback for the ended session
+	s.scheduler.Cancel(channelID)
+	s.syncer.Forget(channelID)
+
+	if session.Playback.IsPlaying {
+		results := s.fanOut(ctx, session.Participants, func(ctx context.Context, client *api.Client, device api.Device) error {
+			return client.Pause(ctx, device.ID)
+		})
+		s.notifyFailures(results, "pause playback")
+	}
+
+	return session, nil
+}
@@ -0,0 +1,175 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"jam-bot/internal/spotify/api"
+)
+
+// ErrUnsupportedLink is returned for Spotify links to things that can't be queued, such as artists or podcasts
+var ErrUnsupportedLink = errors.New("only track, album and playlist links can be added")
+
+// ErrLinkNotFound is returned when a link points to something that doesn't exist or is private
+var ErrLinkNotFound = errors.New("nothing found at that Spotify link; it may be private or removed")
+
+// maxLinkTracks caps how many songs a single album or playlist link adds to the queue
+const maxLinkTracks = 200
+
+// LinkKind is the kind of Spotify object a link points to
+type LinkKind string
+
+// Link kinds that can be queued
+const (
+	LinkTrack    LinkKind = "track"
+	LinkAlbum    LinkKind = "album"
+	LinkPlaylist LinkKind = "playlist"
+)
+
+// Link is a Spotify URL or URI
+type Link struct {
+	Kind LinkKind
+	ID   string
+}
+
+// ResolvedLink is what a link added: the songs in play order and the name to show for them
+type ResolvedLink struct {
+	Link
+	Name      string // Track title, album name or playlist name
+	Songs     []Song
+	Truncated bool // The album or playlist had more than maxLinkTracks songs
+}
+
+// ParseLink recognises open.spotify.com URLs, with or without ?si= tracking parameters and
+// intl-xx path prefixes, and spotify: URIs. ok is false if input isn't a Spotify link at all;
+// links to objects other than tracks, albums and playlists are returned with their own kind.
+func ParseLink(input string) (link Link, ok bool) {
+	// Discord users wrap links in angle brackets to suppress the embed
+	input = strings.TrimSpace(input)
+	input = strings.TrimSuffix(strings.TrimPrefix(input, "<"), ">")
+
+	var parts []string
+	if strings.HasPrefix(input, "spotify:") {
+		parts = strings.Split(input, ":")[1:]
+	} else {
+		if !strings.Contains(input, "://") {
+			input = "https://" + input
+		}
+		u, err := url.Parse(input)
+		if err != nil {
+			return Link{}, false
+		}
+		switch strings.ToLower(u.Hostname()) {
+		case "open.spotify.com", "play.spotify.com":
+		default:
+			return Link{}, false
+		}
+
+		for _, part := range strings.Split(u.Path, "/") {
+			if part == "" || part == "embed" || strings.HasPrefix(part, "intl-") {
+				continue
+			}
+			parts = append(parts, part)
+		}
+	}
+
+	// Older links name the playlist owner first, as in user/<owner>/playlist/<id>
+	if len(parts) < 2 {
+		return Link{}, false
+	}
+	kind, id := parts[len(parts)-2], parts[len(parts)-1]
+	if !isSpotifyID(id) {
+		return Link{}, false
+	}
+
+	return Link{Kind: LinkKind(strings.ToLower(kind)), ID: id}, true
+}
+
+// isSpotifyID reports whether id looks like a base-62 Spotify ID
+func isSpotifyID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveLink looks up the songs a link points to using the user's Spotify account
+func (s *Service) ResolveLink(ctx context.Context, userID string, link Link) (ResolvedLink, error) {
+	client, err := s.apiClient(ctx, userID)
+	if err != nil {
+		return ResolvedLink{}, fmt.Errorf("failed to get Spotify client: %w", err)
+	}
+
+	resolved := ResolvedLink{Link: link}
+	var tracks []api.Track
+
+	switch link.Kind {
+	case LinkTrack:
+		track, err := client.Track(ctx, link.ID)
+		if err != nil {
+			return ResolvedLink{}, linkError(err)
+		}
+		resolved.Name = track.Name
+		tracks = []api.Track{*track}
+
+	case LinkAlbum:
+		album, err := client.Album(ctx, link.ID)
+		if err != nil {
+			return ResolvedLink{}, linkError(err)
+		}
+		resolved.Name = album.Name
+
+		// Ask for one extra track to tell whether the album was cut short
+		tracks, err = client.AlbumTracks(ctx, link.ID, maxLinkTracks+1)
+		if err != nil {
+			return ResolvedLink{}, linkError(err)
+		}
+		for i := range tracks {
+			tracks[i].Album = *album
+		}
+
+	case LinkPlaylist:
+		playlist, err := client.Playlist(ctx, link.ID)
+		if err != nil {
+			return ResolvedLink{}, linkError(err)
+		}
+		resolved.Name = playlist.Name
+
+		tracks, err = client.PlaylistTracks(ctx, link.ID, maxLinkTracks+1)
+		if err != nil {
+			return ResolvedLink{}, linkError(err)
+		}
+
+	default:
+		return ResolvedLink{}, ErrUnsupportedLink
+	}
+
+	if len(tracks) > maxLinkTracks {
+		tracks = tracks[:maxLinkTracks]
+		resolved.Truncated = true
+	}
+	if len(tracks) == 0 {
+		return ResolvedLink{}, fmt.Errorf("%s has no playable songs", resolved.Name)
+	}
+
+	for _, track := range tracks {
+		resolved.Songs = append(resolved.Songs, songFromTrack(track))
+	}
+	return resolved, nil
+}
+
+// linkError turns a failed lookup into a user-facing error
+func linkError(err error) error {
+	if errors.Is(err, api.ErrNotFound) {
+		return ErrLinkNotFound
+	}
+	return fmt.Errorf("failed to look up link: %w", err)
+}
@@ -0,0 +1,118 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"jam-bot/internal/spotify/api"
+)
+
+// AddedAt returns when the song was queued, or the zero time for songs queued before this was recorded
+func (song Song) AddedAt() time.Time {
+	if song.AddedAtMs == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(song.AddedAtMs)
+}
+
+// needsMetadata reports whether a track was stored before album and artwork details were kept
+func (song Song) needsMetadata() bool {
+	return strings.HasPrefix(song.URI, "spotify:track:") && song.Album == ""
+}
+
+// fillMetadata copies the Spotify details of looked-up into the song, keeping who queued it and when
+func (song *Song) fillMetadata(lookedUp Song) {
+	if song.DurationMs == 0 {
+		song.DurationMs = lookedUp.DurationMs
+	}
+	song.Album = lookedUp.Album
+	song.ArtworkURL = lookedUp.ArtworkURL
+	song.Explicit = lookedUp.Explicit
+	song.Popularity = lookedUp.Popularity
+	song.ExternalURL = lookedUp.ExternalURL
+}
+
+// eachSong calls fn for every song the session holds: the current song, the queue and the history
+func (session *Session) eachSong(fn func(song *Song)) {
+	if session.Playback.CurrentSong.URI != "" {
+		fn(&session.Playback.CurrentSong)
+	}
+	for i := range session.Queue {
+		fn(&session.Queue[i])
+	}
+	for i := range session.History {
+		fn(&session.History[i])
+	}
+}
+
+// BackfillMetadata looks up the details missing from songs that were queued before they were stored,
+// updating the sessions in place. Failures are logged; the songs keep working without the details.
+func (s *Service) BackfillMetadata(ctx context.Context, sessions []Session) {
+	for i := range sessions {
+		err := s.backfillSession(ctx, &sessions[i])
+		if err != nil {
+			log.Printf("[WARN] Failed to backfill song details for channel %s: %v", sessions[i].ChannelID, err)
+		}
+	}
+}
+
+// backfillSession fills in the details of a session's songs using the first participant able to look them up
+func (s *Service) backfillSession(ctx context.Context, session *Session) error {
+	seen := make(map[string]bool)
+	var trackIDs []string
+	session.eachSong(func(song *Song) {
+		if song.needsMetadata() && !seen[song.URI] {
+			seen[song.URI] = true
+			trackIDs = append(trackIDs, strings.TrimPrefix(song.URI, "spotify:track:"))
+		}
+	})
+	if len(trackIDs) == 0 {
+		return nil
+	}
+
+	var client *api.Client
+	for _, userID := range session.Participants {
+		c, err := s.apiClient(ctx, userID)
+		if err == nil {
+			client = c
+			break
+		}
+	}
+	if client == nil {
+		return errors.New("no participant is linked to Spotify")
+	}
+
+	tracks, err := client.Tracks(ctx, trackIDs)
+	if err != nil {
+		return fmt.Errorf("failed to look up tracks: %w", err)
+	}
+
+	lookedUp := make(map[string]Song, len(tracks))
+	for _, track := range tracks {
+		lookedUp[track.URI] = songFromTrack(track)
+	}
+
+	var filled int
+	updated, err := s.sessions.UpdateSession(ctx, session.ChannelID, func(session *Session) error {
+		filled = 0
+		session.eachSong(func(song *Song) {
+			details, ok := lookedUp[song.URI]
+			if ok && song.needsMetadata() {
+				song.fillMetadata(details)
+				filled++
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	*session = *updated
+	log.Printf("[INFO] Backfilled details of %d songs in channel %s", filled, session.ChannelID)
+	return nil
+}
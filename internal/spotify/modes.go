@@ -0,0 +1,165 @@
+package spotify
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RepeatMode controls what happens when the current song or the whole queue finishes
+type RepeatMode string
+
+// Repeat modes; an empty mode means RepeatOff
+const (
+	RepeatOff   RepeatMode = "off"
+	RepeatTrack RepeatMode = "track" // Replay the current song
+	RepeatQueue RepeatMode = "queue" // Send finished songs back to the end of the queue
+)
+
+// ParseRepeatMode parses a repeat mode name
+func ParseRepeatMode(name string) (RepeatMode, error) {
+	switch mode := RepeatMode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case RepeatOff, RepeatTrack, RepeatQueue:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown repeat mode %q", name)
+}
+
+// RepeatMode returns the session's repeat mode, treating an unset mode as RepeatOff
+func (session *Session) RepeatMode() RepeatMode {
+	if session.Repeat == "" {
+		return RepeatOff
+	}
+	return session.Repeat
+}
+
+// shuffleKey orders a song within a shuffled queue. The same seed always gives the same order,
+// and the song's sequence number keeps duplicates of one track apart.
+func shuffleKey(seed int64, song Song) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(seed))
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(song.Seq))
+	h.Write(buf[:])
+	h.Write([]byte(song.URI))
+	return h.Sum64()
+}
+
+// enqueue adds a song to the end of the queue, or to its shuffled place when shuffle is on
+func (session *Session) enqueue(song Song) {
+	session.insertUpcoming(session.stamp(song))
+}
+
+// stamp numbers a song being added to the session and records when it was added
+func (session *Session) stamp(song Song) Song {
+	session.NextSeq++
+	song.Seq = session.NextSeq
+	song.AddedAtMs = time.Now().UnixMilli()
+	return song
+}
+
+// insertUpcoming places a song among the upcoming songs, keeping the playing song at the head
+func (session *Session) insertUpcoming(song Song) {
+	if session.Fair {
+		start := session.firstMovable()
+		index := start + fairInsertIndex(session.currentSong(), session.Queue[start:], song, session.FairWeights)
+
+		session.Queue = append(session.Queue, Song{})
+		copy(session.Queue[index+1:], session.Queue[index:])
+		session.Queue[index] = song
+		return
+	}
+
+	if !session.Shuffle || len(session.Queue) == 0 {
+		session.Queue = append(session.Queue, song)
+		return
+	}
+
+	// Go before the first upcoming song with a larger key
+	key := shuffleKey(session.ShuffleSeed, song)
+	index := len(session.Queue)
+	for i := session.firstMovable(); i < len(session.Queue); i++ {
+		if shuffleKey(session.ShuffleSeed, session.Queue[i]) > key {
+			index = i
+			break
+		}
+	}
+
+	session.Queue = append(session.Queue, Song{})
+	copy(session.Queue[index+1:], session.Queue[index:])
+	session.Queue[index] = song
+}
+
+// reorderUpcoming sorts the songs after the playing one, or every song when nothing is playing,
+// into shuffled or added order, then interleaves the requesters when the fair queue is on
+func (session *Session) reorderUpcoming() {
+	if len(session.Queue) < 2 {
+		return
+	}
+
+	upcoming := session.Queue[session.firstMovable():]
+	if session.Shuffle {
+		sort.SliceStable(upcoming, func(i, j int) bool {
+			return shuffleKey(session.ShuffleSeed, upcoming[i]) < shuffleKey(session.ShuffleSeed, upcoming[j])
+		})
+	} else {
+		// Songs queued before sequence numbers existed keep their place at the front
+		sort.SliceStable(upcoming, func(i, j int) bool {
+			return upcoming[i].Seq < upcoming[j].Seq
+		})
+	}
+
+	if session.Fair {
+		// A playing head is played before the rest either way, so it counts as its requester's turn
+		copy(upcoming, fairOrder(session.currentSong(), upcoming, session.FairWeights))
+	}
+}
+
+// SetShuffle turns shuffling of the upcoming songs on or off. A zero seed picks a new one;
+// reusing a seed reproduces the same order for the same queue.
+func (s *Service) SetShuffle(ctx context.Context, channelID string, enabled bool, seed int64) (*Session, error) {
+	if enabled && seed == 0 {
+		seed = newShuffleSeed()
+	}
+
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		session.Shuffle = enabled
+		if enabled {
+			session.ShuffleSeed = seed
+		}
+		session.reorderUpcoming()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyPlaybackChange(session)
+
+	return session, nil
+}
+
+// newShuffleSeed picks a shuffle seed short enough to type back in
+func newShuffleSeed() int64 {
+	return time.Now().UnixNano()%999999 + 1
+}
+
+// SetRepeat sets the session's repeat mode
+func (s *Service) SetRepeat(ctx context.Context, channelID string, mode RepeatMode) error {
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		session.Repeat = mode
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyPlaybackChange(session)
+
+	return nil
+}
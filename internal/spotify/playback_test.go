@@ -0,0 +1,243 @@
+package spotify_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"jam-bot/internal/config"
+	"jam-bot/internal/spotify"
+	"jam-bot/internal/spotify/spotifytest"
+)
+
+// waitFor polls until cond holds or the timeout passes
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestPlaybackEndToEnd runs a jam session against the fake Spotify server: two users log in,
+// queue two songs and start playback, the first song ends on its own, and a rate limited
+// request is retried after the Retry-After the server sent
+func TestPlaybackEndToEnd(t *testing.T) {
+	ctx := context.Background()
+
+	srv := spotifytest.NewServer()
+	defer srv.Close()
+	srv.AddTracks(
+		spotifytest.Track{Name: "Short Song", Artists: []string{"Band"}, URI: "spotify:track:short", DurationMs: 300},
+		spotifytest.Track{Name: "Long Song", Artists: []string{"Band"}, URI: "spotify:track:long", DurationMs: 60000},
+	)
+
+	cfg := &config.Config{
+		SpotifyClientID:     "client-id",
+		SpotifyClientSecret: "client-secret",
+		SpotifyRedirectURI:  "http://localhost/callback",
+		SpotifyRateLimit:    10,
+		SpotifyRateBurst:    20,
+	}
+	srv.Configure(cfg)
+	store := spotify.NewMemoryStore()
+	s := spotify.NewSpotifyServiceWithStores(cfg, store, store, store, nil)
+
+	// The state is the Discord user; the code names the fake Spotify user
+	for discordID, code := range map[string]string{"alice-discord": "alice", "bob-discord": "bob"} {
+		err := s.HandleCallback(ctx, discordID, code)
+		if err != nil {
+			t.Fatalf("HandleCallback(%s): %v", discordID, err)
+		}
+		isAuth, err := s.IsAuthenticated(ctx, discordID)
+		if err != nil || !isAuth {
+			t.Fatalf("IsAuthenticated(%s) = %v, %v; want true", discordID, isAuth, err)
+		}
+	}
+
+	_, err := s.StartSession(ctx, "guild", "channel", "alice-discord", spotify.SessionSettings{})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	defer s.DeleteSession(ctx, "channel")
+	err = s.AddUserToSession(ctx, "guild", "channel", "bob-discord")
+	if err != nil {
+		t.Fatalf("AddUserToSession: %v", err)
+	}
+
+	for _, query := range []string{"short", "long"} {
+		song, err := s.SearchSong(ctx, "alice-discord", query)
+		if err != nil {
+			t.Fatalf("SearchSong(%q): %v", query, err)
+		}
+		song.AddedBy = "alice-discord"
+		err = s.AddSongToQueue(ctx, "channel", song)
+		if err != nil {
+			t.Fatalf("AddSongToQueue(%q): %v", query, err)
+		}
+	}
+
+	report, err := s.StartPlayback(ctx, "channel")
+	if err != nil {
+		t.Fatalf("StartPlayback: %v", err)
+	}
+	if report.Succeeded() != 2 {
+		t.Fatalf("StartPlayback reached %d participants, want 2: %+v", report.Succeeded(), report.Results)
+	}
+	for _, user := range []string{"alice", "bob"} {
+		player := srv.Player(user)
+		if player.TrackURI != "spotify:track:short" || !player.IsPlaying {
+			t.Errorf("%s is on %q (playing %v), want the short song playing", user, player.TrackURI, player.IsPlaying)
+		}
+	}
+
+	// The short song ends after 300ms and the scheduler moves everyone on to the long one
+	for _, user := range []string{"alice", "bob"} {
+		waitFor(t, 3*time.Second, user+" to move on to the next song", func() bool {
+			return srv.Player(user).TrackURI == "spotify:track:long"
+		})
+	}
+	session, err := s.LoadSession(ctx, "channel")
+	if err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	if session.Playback.CurrentSong.URI != "spotify:track:long" || !session.Playback.IsPlaying {
+		t.Errorf("session is on %q (playing %v), want the long song playing", session.Playback.CurrentSong.URI, session.Playback.IsPlaying)
+	}
+	if len(session.Queue) != 1 || len(session.History) != 1 || session.PlayedCount != 1 {
+		t.Errorf("queue %d, history %d, played %d; want 1, 1, 1", len(session.Queue), len(session.History), session.PlayedCount)
+	}
+
+	// A 429 holds back requests until Retry-After has passed, then the request is sent again
+	retryAfter := time.Second
+	srv.FailNext(1, 429, retryAfter)
+	before := srv.Requests()
+	started := time.Now()
+	song, err := s.SearchSong(ctx, "bob-discord", "long")
+	if err != nil {
+		t.Fatalf("SearchSong after a 429: %v", err)
+	}
+	if song.URI != "spotify:track:long" {
+		t.Errorf("SearchSong after a 429 found %q, want the long song", song.URI)
+	}
+	if elapsed := time.Since(started); elapsed < retryAfter {
+		t.Errorf("the retry was sent after %v, before the %v Retry-After", elapsed, retryAfter)
+	}
+	if sent := srv.Requests() - before; sent != 2 {
+		t.Errorf("the search took %d requests, want 2", sent)
+	}
+}
+
+// TestStartPlaybackWithoutDMs checks that participants whom playback can't reach are reported,
+// even when the service has no way to DM them
+func TestStartPlaybackWithoutDMs(t *testing.T) {
+	ctx := context.Background()
+
+	srv := spotifytest.NewServer()
+	defer srv.Close()
+	srv.AddTracks(spotifytest.Track{Name: "Song", Artists: []string{"Band"}, URI: "spotify:track:song", DurationMs: 60000})
+
+	cfg := &config.Config{SpotifyClientID: "client-id", SpotifyClientSecret: "client-secret"}
+	srv.Configure(cfg)
+	store := spotify.NewMemoryStore()
+	s := spotify.NewSpotifyServiceWithStores(cfg, store, store, store, nil)
+
+	err := s.HandleCallback(ctx, "alice-discord", "alice")
+	if err != nil {
+		t.Fatalf("HandleCallback: %v", err)
+	}
+	_, err = s.StartSession(ctx, "guild", "channel", "alice-discord", spotify.SessionSettings{})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	defer s.DeleteSession(ctx, "channel")
+
+	// Bob never logged in, so starting playback fails for him
+	err = s.AddUserToSession(ctx, "guild", "channel", "bob-discord")
+	if err != nil {
+		t.Fatalf("AddUserToSession: %v", err)
+	}
+	err = s.AddSongToQueue(ctx, "channel", spotify.Song{Title: "Song", Artist: "Band", URI: "spotify:track:song", DurationMs: 60000})
+	if err != nil {
+		t.Fatalf("AddSongToQueue: %v", err)
+	}
+
+	report, err := s.StartPlayback(ctx, "channel")
+	if err != nil {
+		t.Fatalf("StartPlayback: %v", err)
+	}
+	if report.Succeeded() != 1 || len(report.Results) != 2 {
+		t.Errorf("StartPlayback reached %d of %d participants, want 1 of 2", report.Succeeded(), len(report.Results))
+	}
+}
+
+func TestSkipTrack(t *testing.T) {
+	tests := []struct {
+		name        string
+		start       bool // Start the first song before skipping
+		pause       bool // Pause it again before skipping
+		wantPlaying bool
+		wantPlayed  int
+	}{
+		{name: "playing", start: true, wantPlaying: true, wantPlayed: 1},
+		{name: "paused", start: true, pause: true, wantPlayed: 1},
+		{name: "never started"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := spotify.NewMemoryStore()
+			s := spotify.NewSpotifyServiceWithStores(&config.Config{}, store, store, store, nil)
+
+			_, err := s.StartSession(ctx, "guild", "channel", "host", spotify.SessionSettings{})
+			if err != nil {
+				t.Fatalf("StartSession: %v", err)
+			}
+			defer s.DeleteSession(ctx, "channel")
+			for _, id := range []string{"first", "second"} {
+				err = s.AddSongToQueue(ctx, "channel", spotify.Song{Title: id, URI: "spotify:track:" + id, DurationMs: 60000})
+				if err != nil {
+					t.Fatalf("AddSongToQueue: %v", err)
+				}
+			}
+			if tt.start {
+				_, err = s.StartPlayback(ctx, "channel")
+				if err != nil {
+					t.Fatalf("StartPlayback: %v", err)
+				}
+			}
+			if tt.pause {
+				_, err = s.PausePlayback(ctx, "channel")
+				if err != nil {
+					t.Fatalf("PausePlayback: %v", err)
+				}
+			}
+
+			report, err := s.SkipTrack(ctx, "channel")
+			if err != nil {
+				t.Fatalf("SkipTrack: %v", err)
+			}
+			if report.Song.Title != "second" || report.Paused == tt.wantPlaying {
+				t.Errorf("SkipTrack reported %q (paused %v), want second (paused %v)", report.Song.Title, report.Paused, !tt.wantPlaying)
+			}
+
+			session, err := s.LoadSession(ctx, "channel")
+			if err != nil {
+				t.Fatalf("LoadSession: %v", err)
+			}
+			if session.Playback.IsPlaying != tt.wantPlaying {
+				t.Errorf("session playing is %v, want %v", session.Playback.IsPlaying, tt.wantPlaying)
+			}
+			if session.PlayedCount != tt.wantPlayed || len(session.History) != tt.wantPlayed {
+				t.Errorf("played %d, history %d; want %d", session.PlayedCount, len(session.History), tt.wantPlayed)
+			}
+			if len(session.Queue) != 1 || session.Queue[0].Title != "second" {
+				t.Errorf("queue is %v, want just the second song", session.Queue)
+			}
+		})
+	}
+}
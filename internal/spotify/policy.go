@@ -0,0 +1,135 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPolicy is returned when a queue policy has a limit that can't be applied
+var ErrInvalidPolicy = errors.New("invalid queue policy")
+
+// PolicyRule names a queue policy rule in messages to users
+type PolicyRule string
+
+// Queue policy rules
+const (
+	RuleQueueLength    PolicyRule = "queue length"
+	RulePerUser        PolicyRule = "songs per person"
+	RuleSongLength     PolicyRule = "song length"
+	RuleExplicit       PolicyRule = "explicit songs"
+	RuleDuplicates     PolicyRule = "duplicates"
+	RuleRecentlyPlayed PolicyRule = "recent repeats"
+)
+
+// QueuePolicy limits what can be added to the jam sessions of a guild. Zero values mean no limit.
+type QueuePolicy struct {
+	MaxQueueLength int  `json:"max_queue_length,omitempty"` // Songs in the queue, counting the playing one
+	MaxPerUser     int  `json:"max_per_user,omitempty"`     // Songs one person can have in the queue
+	MaxDurationMs  int  `json:"max_duration_ms,omitempty"`  // Longest song that can be added
+	NoExplicit     bool `json:"no_explicit,omitempty"`      // Reject songs marked explicit
+	NoDuplicates   bool `json:"no_duplicates,omitempty"`    // Reject songs that are already queued
+	RecentLimit    int  `json:"recent_limit,omitempty"`     // Reject songs among this many last played
+}
+
+// PolicyError is returned when a queue policy rule blocks a song
+type PolicyError struct {
+	Rule   PolicyRule
+	Song   Song
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("blocked by the %s rule: %s", e.Rule, e.Reason)
+}
+
+// check returns the first rule that keeps song out of the session's queue, or nil if it may be added
+func (p QueuePolicy) check(session *Session, song Song) *PolicyError {
+	blocked := func(rule PolicyRule, format string, args ...interface{}) *PolicyError {
+		return &PolicyError{Rule: rule, Song: song, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if p.NoExplicit && song.Explicit {
+		return blocked(RuleExplicit, "explicit songs aren't allowed")
+	}
+
+	if p.MaxDurationMs > 0 && song.DurationMs > p.MaxDurationMs {
+		seconds := p.MaxDurationMs / 1000
+		return blocked(RuleSongLength, "songs can be at most %d:%02d long", seconds/60, seconds%60)
+	}
+
+	if p.NoDuplicates {
+		for _, queued := range session.Queue {
+			if queued.URI == song.URI {
+				return blocked(RuleDuplicates, "%s is already in the queue", song.Title)
+			}
+		}
+	}
+
+	if p.RecentLimit > 0 {
+		start := len(session.History) - p.RecentLimit
+		if start < 0 {
+			start = 0
+		}
+		for _, played := range session.History[start:] {
+			if played.URI == song.URI {
+				return blocked(RuleRecentlyPlayed, "%s was played within the last %d songs", song.Title, p.RecentLimit)
+			}
+		}
+	}
+
+	if p.MaxQueueLength > 0 && len(session.Queue) >= p.MaxQueueLength {
+		return blocked(RuleQueueLength, "the queue is full at %d songs", p.MaxQueueLength)
+	}
+
+	if p.MaxPerUser > 0 && song.AddedBy != "" {
+		count := 0
+		for _, queued := range session.Queue {
+			if queued.AddedBy == song.AddedBy {
+				count++
+			}
+		}
+		if count >= p.MaxPerUser {
+			return blocked(RulePerUser, "everyone can have at most %d songs in the queue", p.MaxPerUser)
+		}
+	}
+
+	return nil
+}
+
+// QueuePolicy returns the queue policy of a guild; sessions outside a guild have no limits
+func (s *Service) QueuePolicy(ctx context.Context, guildID string) (QueuePolicy, error) {
+	if guildID == "" {
+		return QueuePolicy{}, nil
+	}
+	return s.preferences.GetQueuePolicy(ctx, guildID)
+}
+
+// SetQueuePolicy replaces the queue policy of a guild
+func (s *Service) SetQueuePolicy(ctx context.Context, guildID string, policy QueuePolicy) error {
+	if guildID == "" {
+		return fmt.Errorf("%w: policies can only be set in a server", ErrInvalidPolicy)
+	}
+	if policy.RecentLimit > maxHistoryLength {
+		return fmt.Errorf("%w: only the last %d played songs are remembered", ErrInvalidPolicy, maxHistoryLength)
+	}
+	if policy.MaxQueueLength < 0 || policy.MaxPerUser < 0 || policy.MaxDurationMs < 0 || policy.RecentLimit < 0 {
+		return fmt.Errorf("%w: limits can't be negative", ErrInvalidPolicy)
+	}
+
+	return s.preferences.SetQueuePolicy(ctx, guildID, policy)
+}
+
+// sessionPolicy returns the queue policy of the guild a channel's session belongs to
+func (s *Service) sessionPolicy(ctx context.Context, channelID string) (QueuePolicy, error) {
+	session, err := s.sessions.LoadSession(ctx, channelID)
+	if err != nil {
+		return QueuePolicy{}, err
+	}
+
+	policy, err := s.QueuePolicy(ctx, session.GuildID)
+	if err != nil {
+		return QueuePolicy{}, fmt.Errorf("failed to load queue policy: %w", err)
+	}
+	return policy, nil
+}
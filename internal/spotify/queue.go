@@ -0,0 +1,256 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"jam-bot/internal/spotify/api"
+)
+
+// ErrPositionOutOfRange is returned when a queue position doesn't exist
+var ErrPositionOutOfRange = errors.New("song position out of range")
+
+// ErrCurrentSongLocked is returned when an edit would move the song that is playing
+var ErrCurrentSongLocked = errors.New("the current song can't be moved")
+
+// hasCurrent reports whether the head of the queue is the session's current song
+func (session *Session) hasCurrent() bool {
+	return len(session.Queue) > 0 &&
+		session.Playback.CurrentSong.URI != "" &&
+		session.Queue[0].URI == session.Playback.CurrentSong.URI
+}
+
+// firstMovable returns the index of the first song that may be reordered; the current song stays at the head
+func (session *Session) firstMovable() int {
+	if session.hasCurrent() {
+		return 1
+	}
+	return 0
+}
+
+// checkMovable validates that index refers to a song that may be reordered
+func (session *Session) checkMovable(index int) error {
+	if index < 0 || index >= len(session.Queue) {
+		return ErrPositionOutOfRange
+	}
+	if index < session.firstMovable() {
+		return ErrCurrentSongLocked
+	}
+	return nil
+}
+
+// removeSongs removes the songs at the given ascending indexes, resetting playback if the current song is among them
+func (session *Session) removeSongs(indexes []int) (removed []Song, removedCurrent bool) {
+	removedCurrent = len(indexes) > 0 && indexes[0] == 0 && session.hasCurrent()
+
+	kept := make([]Song, 0, len(session.Queue))
+	next := 0
+	for i, song := range session.Queue {
+		if next < len(indexes) && indexes[next] == i {
+			removed = append(removed, song)
+			next++
+			continue
+		}
+		kept = append(kept, song)
+	}
+	session.Queue = kept
+
+	if removedCurrent {
+		session.Playback.Reset(time.Now())    // Reset position
+		session.Playback.CurrentSong = Song{} // Clear current song
+	}
+	return removed, removedCurrent
+}
+
+// afterCurrentRemoved stops timers and votes for a current song that was taken out of the queue.
+// If the song was playing, the new head of the queue starts in its place, or everyone is paused
+// when nothing is left. It reports whether a new song was started, which notifies the change itself.
+func (s *Service) afterCurrentRemoved(ctx context.Context, session *Session, song Song, wasPlaying bool) bool {
+	channelID := session.ChannelID
+	s.scheduler.Cancel(channelID)
+
+	err := s.ClearSkipVotes(ctx, channelID, song.URI)
+	if err != nil {
+		log.Printf("[WARN] Failed to clear skip votes for channel %s: %v", channelID, err)
+	}
+
+	if !wasPlaying {
+		return false
+	}
+
+	if len(session.Queue) > 0 {
+		_, err = s.StartPlayback(ctx, channelID)
+		if err != nil {
+			log.Printf("[WARN] Failed to start the next song in channel %s: %v", channelID, err)
+			return false
+		}
+		return true
+	}
+
+	s.syncer.Stop(channelID)
+	results := s.fanOut(ctx, session.Participants, func(ctx context.Context, client *api.Client, device api.Device) error {
+		return client.Pause(ctx, device.ID)
+	})
+	s.notifyFailures(results, "pause playback")
+	return false
+}
+
+// RemoveSongRange removes the songs from index from to index to, inclusive.
+// Removing the song that is playing moves on to the next one.
+func (s *Service) RemoveSongRange(ctx context.Context, channelID string, from, to int) ([]Song, error) {
+	var removed []Song
+	var removedCurrent, wasPlaying bool
+
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		wasPlaying = session.Playback.IsPlaying
+		if from < 0 || to >= len(session.Queue) || from > to {
+			return ErrPositionOutOfRange
+		}
+
+		indexes := make([]int, 0, to-from+1)
+		for i := from; i <= to; i++ {
+			indexes = append(indexes, i)
+		}
+		removed, removedCurrent = session.removeSongs(indexes)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if removedCurrent && s.afterCurrentRemoved(ctx, session, removed[0], wasPlaying) {
+		return removed, nil
+	}
+	s.notifyPlaybackChange(session)
+
+	return removed, nil
+}
+
+// RemoveSongsByUser removes every upcoming song a user added; the current song keeps playing
+func (s *Service) RemoveSongsByUser(ctx context.Context, channelID, userID string) ([]Song, error) {
+	var removed []Song
+
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		var indexes []int
+		for i := session.firstMovable(); i < len(session.Queue); i++ {
+			if session.Queue[i].AddedBy == userID {
+				indexes = append(indexes, i)
+			}
+		}
+		removed, _ = session.removeSongs(indexes)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyPlaybackChange(session)
+
+	return removed, nil
+}
+
+// ClearQueue removes every upcoming song, keeping the current one, and returns how many were removed
+func (s *Service) ClearQueue(ctx context.Context, channelID string) (int, error) {
+	var cleared int
+
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		keep := session.firstMovable()
+		cleared = len(session.Queue) - keep
+		session.Queue = session.Queue[:keep]
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.notifyPlaybackChange(session)
+
+	return cleared, nil
+}
+
+// MoveSong moves the song at index from to index to, shifting the songs in between
+func (s *Service) MoveSong(ctx context.Context, channelID string, from, to int) (Song, error) {
+	var moved Song
+
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		if err := session.checkMovable(from); err != nil {
+			return err
+		}
+		if err := session.checkMovable(to); err != nil {
+			return err
+		}
+
+		moved = session.Queue[from]
+		if from < to {
+			copy(session.Queue[from:to], session.Queue[from+1:to+1])
+		} else {
+			copy(session.Queue[to+1:from+1], session.Queue[to:from])
+		}
+		session.Queue[to] = moved
+		return nil
+	})
+	if err != nil {
+		return Song{}, err
+	}
+
+	s.notifyPlaybackChange(session)
+
+	return moved, nil
+}
+
+// SwapSongs swaps the songs at indexes a and b
+func (s *Service) SwapSongs(ctx context.Context, channelID string, a, b int) (Song, Song, error) {
+	var first, second Song
+
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		if err := session.checkMovable(a); err != nil {
+			return err
+		}
+		if err := session.checkMovable(b); err != nil {
+			return err
+		}
+
+		session.Queue[a], session.Queue[b] = session.Queue[b], session.Queue[a]
+		first, second = session.Queue[a], session.Queue[b]
+		return nil
+	})
+	if err != nil {
+		return Song{}, Song{}, err
+	}
+
+	s.notifyPlaybackChange(session)
+
+	return first, second, nil
+}
+
+// PlayNext adds a song right after the current one, ahead of the rest of the queue, and returns its index.
+// The guild's queue policy applies as it does to songs added at the end.
+func (s *Service) PlayNext(ctx context.Context, channelID string, song Song) (int, error) {
+	policy, err := s.sessionPolicy(ctx, channelID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	var index int
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		if blocked := policy.check(session, song); blocked != nil {
+			return blocked
+		}
+
+		index = session.firstMovable()
+		session.Queue = append(session.Queue, Song{})
+		copy(session.Queue[index+1:], session.Queue[index:])
+		session.Queue[index] = session.stamp(song)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	s.notifyPlaybackChange(session)
+
+	return index, nil
+}
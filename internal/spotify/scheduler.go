@@ -0,0 +1,162 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// trackEndTimeout bounds how long advancing to the next song may take
+const trackEndTimeout = 30 * time.Second
+
+// Scheduler keeps one timer per session that fires when the current song should end
+type Scheduler struct {
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+	onEnd  func(channelID, trackURI string)
+}
+
+// NewScheduler creates a scheduler that calls onEnd when a session's song finishes
+func NewScheduler(onEnd func(channelID, trackURI string)) *Scheduler {
+	return &Scheduler{
+		timers: make(map[string]*time.Timer),
+		onEnd:  onEnd,
+	}
+}
+
+// Schedule (re)arms the timer for a session based on its current playback state
+func (sc *Scheduler) Schedule(session *Session) {
+	channelID := session.ChannelID
+	playback := session.Playback
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.stopLocked(channelID)
+
+	// Without a known duration there is no way to tell when the song ends
+	if !playback.IsPlaying || playback.CurrentSong.URI == "" || playback.CurrentSong.DurationMs <= 0 {
+		return
+	}
+
+	// Account for the time that has passed since the playback state was last saved
+	position := playback.PositionAt(time.Now())
+	remaining := time.Duration(playback.CurrentSong.DurationMs-position) * time.Millisecond
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	trackURI := playback.CurrentSong.URI
+	var timer *time.Timer
+	timer = time.AfterFunc(remaining, func() {
+		sc.mu.Lock()
+		// A newer timer replaced this one; let it handle the session
+		if sc.timers[channelID] != timer {
+			sc.mu.Unlock()
+			return
+		}
+		delete(sc.timers, channelID)
+		sc.mu.Unlock()
+
+		sc.onEnd(channelID, trackURI)
+	})
+	sc.timers[channelID] = timer
+}
+
+// Cancel stops the timer for a session, if any
+func (sc *Scheduler) Cancel(channelID string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.stopLocked(channelID)
+}
+
+// stopLocked stops and forgets a session's timer; sc.mu must be held
+func (sc *Scheduler) stopLocked(channelID string) {
+	if timer, ok := sc.timers[channelID]; ok {
+		timer.Stop()
+		delete(sc.timers, channelID)
+	}
+}
+
+// ResumeScheduling rebuilds the playback timers and sync loops for sessions loaded from storage
+func (s *Service) ResumeScheduling(sessions []Session) {
+	for i := range sessions {
+		s.scheduler.Schedule(&sessions[i])
+		if sessions[i].Playback.IsPlaying {
+			s.syncer.Start(sessions[i].ChannelID)
+		}
+	}
+}
+
+// handleTrackEnd is called by the scheduler when a session's current song has finished
+func (s *Service) handleTrackEnd(channelID, trackURI string) {
+	ctx, cancel := context.WithTimeout(context.Background(), trackEndTimeout)
+	defer cancel()
+
+	err := s.advanceQueue(ctx, channelID, trackURI)
+	if err != nil {
+		log.Printf("[ERROR] Failed to advance queue for channel %s: %v", channelID, err)
+	}
+}
+
+// errStaleTimer aborts an advance whose timer no longer matches the session
+var errStaleTimer = errors.New("session moved on since the timer was armed")
+
+// advanceQueue pops the finished song off the queue and starts the next one for every participant.
+// With repeat-track on, the finished song starts over instead.
+func (s *Service) advanceQueue(ctx context.Context, channelID, finishedURI string) error {
+	var repeatTrack bool
+
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		// The session moved on (skip, remove, pause) since the timer was armed
+		if !session.Playback.IsPlaying || session.Playback.CurrentSong.URI != finishedURI {
+			return errStaleTimer
+		}
+		if len(session.Queue) == 0 || session.Queue[0].URI != finishedURI {
+			return errStaleTimer
+		}
+
+		repeatTrack = session.RepeatMode() == RepeatTrack
+		if repeatTrack {
+			// Same song, so StartPlayback resumes it from the rewound clock
+			session.Playback.Reset(time.Now())
+			return nil
+		}
+
+		session.popCurrent()
+		return nil
+	})
+	if errors.Is(err, errStaleTimer) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if repeatTrack {
+		// Each play of the song gets a fresh vote
+		err = s.ClearSkipVotes(ctx, channelID, finishedURI)
+		if err != nil {
+			log.Printf("[WARN] Failed to clear skip votes for channel %s: %v", channelID, err)
+		}
+	}
+
+	if len(session.Queue) == 0 {
+		log.Printf("[INFO] Queue finished for channel %s", channelID)
+		s.syncer.Stop(channelID)
+		s.notifyPlaybackChange(session)
+		return nil
+	}
+
+	report, err := s.StartPlayback(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if failed := len(report.Failed()); failed > 0 {
+		log.Printf("[WARN] Could not start %s for %d participant(s) in channel %s", report.Song.Title, failed, channelID)
+	}
+	return nil
+}
@@ -2,59 +2,110 @@ package spotify
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
-	"net/url"
 	"strings"
 	"time"
 
 	"jam-bot/internal/config"
+	"jam-bot/internal/spotify/api"
 
 	"github.com/go-redis/redis/v8"
 	"golang.org/x/oauth2"
 )
 
 type Song struct {
-	Title  string `json:"title"`
-	Artist string `json:"artist"`
-	URI    string `json:"uri"`
+	Title       string `json:"title"`
+	Artist      string `json:"artist"`
+	URI         string `json:"uri"`
+	DurationMs  int    `json:"duration_ms,omitempty"`
+	Album       string `json:"album,omitempty"`
+	ArtworkURL  string `json:"artwork_url,omitempty"`  // Largest album cover
+	Explicit    bool   `json:"explicit,omitempty"`     // Has explicit lyrics
+	Popularity  int    `json:"popularity,omitempty"`   // 0-100, as rated by Spotify
+	ExternalURL string `json:"external_url,omitempty"` // Link to the track on open.spotify.com
+	AddedBy     string `json:"added_by,omitempty"`     // Discord user ID of whoever queued the song
+	AddedAtMs   int64  `json:"added_at_ms,omitempty"`  // Unix milliseconds when the song was queued
+	Seq         int64  `json:"seq,omitempty"`          // Order in which the song was added to its session
 }
 
 type PlaybackState struct {
-	CurrentSong   Song  `json:"current_song"`
-	PositionMs    int   `json:"position_ms"`
-	IsPlaying     bool  `json:"is_playing"`
-	LastUpdatedAt int64 `json:"last_updated_at"` // Unix timestamp in seconds
+	CurrentSong Song `json:"current_song"`
+	SessionClock
 }
 
 type Session struct {
-	ChannelID    string        `json:"channel_id"` // Changed from GuildID to ChannelID
-	Participants []string      `json:"participants"`
-	Queue        []Song        `json:"queue"`
-	Playback     PlaybackState `json:"playback"`
+	ChannelID    string         `json:"channel_id"`              // Changed from GuildID to ChannelID
+	GuildID      string         `json:"guild_id,omitempty"`      // Guild whose queue policy applies
+	Host         string         `json:"host,omitempty"`          // User who started the session
+	CreatedAtMs  int64          `json:"created_at_ms,omitempty"` // When the session was started; 0 for older sessions
+	PlayedCount  int            `json:"played_count,omitempty"`  // Songs finished or skipped; History only keeps the last ones
+	Participants []string       `json:"participants"`
+	Queue        []Song         `json:"queue"`
+	History      []Song         `json:"history,omitempty"` // Most recently played song last
+	Playback     PlaybackState  `json:"playback"`
+	Shuffle      bool           `json:"shuffle,omitempty"`
+	ShuffleSeed  int64          `json:"shuffle_seed,omitempty"` // Same seed and queue give the same order
+	Repeat       RepeatMode     `json:"repeat,omitempty"`
+	Fair         bool           `json:"fair,omitempty"`         // Interleave requesters round-robin
+	FairWeights  map[string]int `json:"fair_weights,omitempty"` // Songs per round by requester; 1 if missing
+	NextSeq      int64          `json:"next_seq,omitempty"`     // Sequence number of the last added song
+	Version      int64          `json:"version"`                // Incremented by every atomic update
 }
 
-// Session Key Prefix
-const sessionKeyPrefix = "jam_session_channel:" // Updated prefix
+// ErrAlreadyInSession is returned when a user joins a session they are already part of
+var ErrAlreadyInSession = errors.New("user is already in the session")
+
+// ErrSeekOutOfRange is returned when seeking past the end of the current song
+var ErrSeekOutOfRange = errors.New("position is past the end of the song")
+
+// maxHistoryLength caps how many played songs are remembered per session
+const maxHistoryLength = 50
 
 // Service represents the Spotify service
 type Service struct {
-	config      *oauth2.Config
-	redisClient *redis.Client
-	SendDM      func(discordUserID, message string) error // Add SendDM function
+	config            *oauth2.Config
+	apiBaseURL        string
+	httpClient        *http.Client // Rate limited, retrying client shared by all Spotify calls
+	tokens            TokenStore
+	preferences       PreferenceStore
+	sessions          SessionStore
+	scheduler         *Scheduler
+	syncer            *SyncEngine
+	voteSkipThreshold float64                                   // Fraction of participants needed to skip a song
+	SendDM            func(discordUserID, message string) error // Add SendDM function
+	OnPlaybackChange  func(session *Session)                    // Called after the playback state of a session changes
 }
 
-// NewSpotifyService initializes the Spotify service with Redis and SendDM function
-func NewSpotifyService(cfg *config.Config, sendDM func(discordUserID, message string) error) *Service {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisAddr,
-		Password: cfg.RedisPassword,
-		DB:       cfg.RedisDB,
-	})
+// NewSpotifyService initializes the Spotify service with the configured storage backend and SendDM function
+func NewSpotifyService(cfg *config.Config, sendDM func(discordUserID, message string) error) (*Service, error) {
+	var tokens TokenStore
+	var preferences PreferenceStore
+	var sessions SessionStore
+	switch cfg.StorageBackend {
+	case config.StorageRedis:
+		rdb := redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+		store := NewRedisStore(rdb)
+		tokens, preferences, sessions = store, store, store
+	case config.StorageMemory:
+		log.Println("[WARN] Using in-memory storage; tokens and sessions will be lost on restart.")
+		store := NewMemoryStore()
+		tokens, preferences, sessions = store, store, store
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+
+	return NewSpotifyServiceWithStores(cfg, tokens, preferences, sessions, sendDM), nil
+}
 
+// NewSpotifyServiceWithStores initializes the Spotify service on top of the given stores
+func NewSpotifyServiceWithStores(cfg *config.Config, tokens TokenStore, preferences PreferenceStore, sessions SessionStore, sendDM func(discordUserID, message string) error) *Service {
 	// Initialize OAuth2 config
 	oauthCfg := &oauth2.Config{
 		ClientID:     cfg.SpotifyClientID,
@@ -62,16 +113,37 @@ func NewSpotifyService(cfg *config.Config, sendDM func(discordUserID, message st
 		RedirectURL:  cfg.SpotifyRedirectURI,
 		Scopes:       []string{"user-read-playback-state", "user-modify-playback-state", "user-read-currently-playing"},
 		Endpoint: oauth2.Endpoint{
-			AuthURL:  "https://accounts.spotify.com/authorize",
-			TokenURL: "https://accounts.spotify.com/api/token",
+			AuthURL:  cfg.SpotifyAccountsBaseURL + "/authorize",
+			TokenURL: cfg.SpotifyAccountsBaseURL + "/api/token",
 		},
 	}
 
-	return &Service{
-		config:      oauthCfg,
-		redisClient: rdb,
-		SendDM:      sendDM,
+	voteSkipThreshold := cfg.VoteSkipThreshold
+	if voteSkipThreshold <= 0 || voteSkipThreshold > 1 {
+		voteSkipThreshold = defaultVoteSkipThreshold
+	}
+
+	// Every user's requests count against the same client ID, so they share one limiter
+	limiter := api.NewLimiter(cfg.SpotifyRateLimit, cfg.SpotifyRateBurst)
+
+	s := &Service{
+		config:            oauthCfg,
+		apiBaseURL:        cfg.SpotifyAPIBaseURL,
+		httpClient:        &http.Client{Transport: api.NewTransport(http.DefaultTransport, limiter)},
+		tokens:            tokens,
+		preferences:       preferences,
+		sessions:          sessions,
+		voteSkipThreshold: voteSkipThreshold,
+		SendDM:            sendDM,
 	}
+	s.scheduler = NewScheduler(s.handleTrackEnd)
+	s.syncer = NewSyncEngine(
+		time.Duration(cfg.SyncIntervalSeconds)*time.Second,
+		time.Duration(cfg.SyncToleranceMs)*time.Millisecond,
+		s.syncTick,
+	)
+
+	return s
 }
 
 // StartAuthServer starts the authentication server on the configured port
@@ -120,23 +192,18 @@ func (s *Service) GetAuthURL(discordUserID string) string {
 	return s.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
 }
 
-// HandleCallback processes the OAuth2 callback and stores the token in Redis
+// HandleCallback processes the OAuth2 callback and stores the token
 func (s *Service) HandleCallback(ctx context.Context, state, code string) error {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, s.httpClient)
 	token, err := s.config.Exchange(ctx, code)
 	if err != nil {
 		return fmt.Errorf("failed to exchange code for token: %w", err)
 	}
 
-	// Serialize token to JSON
-	tokenData, err := json.Marshal(token)
-	if err != nil {
-		return fmt.Errorf("failed to marshal token: %w", err)
-	}
-
-	// Save token in Redis with Discord user ID as key
-	err = s.redisClient.Set(ctx, state, tokenData, time.Hour*24*30).Err()
+	// Save token with Discord user ID as key
+	err = s.tokens.SaveToken(ctx, state, token)
 	if err != nil {
-		return fmt.Errorf("failed to save token to Redis: %w", err)
+		return err
 	}
 
 	log.Printf("[INFO] Successfully authenticated user: %s", state)
@@ -154,60 +221,42 @@ func (s *Service) HandleCallback(ctx context.Context, state, code string) error
 
 // GetClient returns an authenticated http.Client for a given Discord user
 func (s *Service) GetClient(ctx context.Context, discordUserID string) (*http.Client, error) {
-	// Retrieve token from Redis
-	tokenData, err := s.redisClient.Get(ctx, discordUserID).Result()
-	if err == redis.Nil {
-		return nil, fmt.Errorf("user not authenticated with Spotify")
-	} else if err != nil {
-		return nil, fmt.Errorf("failed to get token from Redis: %w", err)
-	}
+	// Route API calls and token refreshes through the shared rate limited client
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, s.httpClient)
 
-	// Deserialize token
-	var token oauth2.Token
-	err = json.Unmarshal([]byte(tokenData), &token)
+	// Retrieve stored token
+	token, err := s.tokens.GetToken(ctx, discordUserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
+		return nil, err
 	}
 
 	// Check if token is expired and refresh if necessary
 	if token.Expiry.Before(time.Now()) && token.RefreshToken != "" {
-		ts := s.config.TokenSource(ctx, &token)
+		ts := s.config.TokenSource(ctx, token)
 		newToken, err := ts.Token()
 		if err != nil {
 			return nil, fmt.Errorf("failed to refresh token: %w", err)
 		}
 
-		// Serialize new token
-		newTokenData, err := json.Marshal(newToken)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal new token: %w", err)
-		}
-
-		// Save new token to Redis
-		err = s.redisClient.Set(ctx, discordUserID, newTokenData, time.Hour*24*30).Err()
+		// Save new token
+		err = s.tokens.SaveToken(ctx, discordUserID, newToken)
 		if err != nil {
-			return nil, fmt.Errorf("failed to save refreshed token to Redis: %w", err)
+			return nil, fmt.Errorf("failed to save refreshed token: %w", err)
 		}
 
-		token = *newToken
+		token = newToken
 	}
 
-	return s.config.Client(ctx, &token), nil
+	return s.config.Client(ctx, token), nil
 }
 
 // IsAuthenticated checks if a Discord user is already authenticated with Spotify
 func (s *Service) IsAuthenticated(ctx context.Context, discordUserID string) (bool, error) {
-	tokenData, err := s.redisClient.Get(ctx, discordUserID).Result()
-	if err == redis.Nil {
+	token, err := s.tokens.GetToken(ctx, discordUserID)
+	if errors.Is(err, ErrTokenNotFound) {
 		return false, nil // No token found
 	} else if err != nil {
-		return false, fmt.Errorf("failed to get token from Redis: %w", err)
-	}
-
-	var token oauth2.Token
-	err = json.Unmarshal([]byte(tokenData), &token)
-	if err != nil {
-		return false, fmt.Errorf("failed to unmarshal token: %w", err)
+		return false, err
 	}
 
 	// Optionally, check if the token is expired and refresh it
@@ -218,111 +267,66 @@ func (s *Service) IsAuthenticated(ctx context.Context, discordUserID string) (bo
 	return true, nil
 }
 
-// CreateSession creates a new jam session for a channel
-func (s *Service) CreateSession(ctx context.Context, channelID string) error {
-	session := Session{
-		ChannelID:    channelID,
-		Participants: []string{},
-		Queue:        []Song{},
-		Playback: PlaybackState{
-			CurrentSong:   Song{},
-			PositionMs:    0,
-			IsPlaying:     false,
-			LastUpdatedAt: time.Now().Unix(),
-		},
-	}
-
-	sessionData, err := json.Marshal(session)
-	if err != nil {
-		return fmt.Errorf("failed to marshal session: %w", err)
-	}
-
-	key := fmt.Sprintf("%s%s", sessionKeyPrefix, channelID)
-	return s.redisClient.Set(ctx, key, sessionData, 0).Err()
-}
-
-// LoadSession loads a jam session from Redis based on ChannelID
+// LoadSession loads a jam session based on ChannelID
 func (s *Service) LoadSession(ctx context.Context, channelID string) (*Session, error) {
-	key := fmt.Sprintf("%s%s", sessionKeyPrefix, channelID)
-	sessionData, err := s.redisClient.Get(ctx, key).Result()
-	if err == redis.Nil {
-		return nil, fmt.Errorf("no active session for channel %s", channelID)
-	} else if err != nil {
-		return nil, fmt.Errorf("failed to get session from Redis: %w", err)
-	}
-
-	var session Session
-	err = json.Unmarshal([]byte(sessionData), &session)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
-	}
-
-	return &session, nil
+	return s.sessions.LoadSession(ctx, channelID)
 }
 
-// SaveSession saves a jam session to Redis based on ChannelID
+// SaveSession saves a jam session based on ChannelID
 func (s *Service) SaveSession(ctx context.Context, session *Session) error {
-	sessionData, err := json.Marshal(session)
-	if err != nil {
-		return fmt.Errorf("failed to marshal session: %w", err)
-	}
-
-	key := fmt.Sprintf("%s%s", sessionKeyPrefix, session.ChannelID)
-	return s.redisClient.Set(ctx, key, sessionData, 0).Err()
+	return s.sessions.SaveSession(ctx, session)
 }
 
-// DeleteSession deletes a jam session from Redis based on ChannelID
+// DeleteSession deletes a jam session based on ChannelID
 func (s *Service) DeleteSession(ctx context.Context, channelID string) error {
-	key := fmt.Sprintf("%s%s", sessionKeyPrefix, channelID)
-	return s.redisClient.Del(ctx, key).Err()
+	s.scheduler.Cancel(channelID)
+	s.syncer.Forget(channelID)
+	_, err := s.sessions.DeleteSession(ctx, channelID)
+	return err
 }
 
-// AddUserToSession adds a user to the jam session for a specific channel
-func (s *Service) AddUserToSession(ctx context.Context, channelID, userID string) error {
-	session, err := s.LoadSession(ctx, channelID)
-	if err != nil {
-		// If no session exists, create one
-		if err.Error() == fmt.Sprintf("no active session for channel %s", channelID) {
-			err = s.CreateSession(ctx, channelID)
-			if err != nil {
-				return fmt.Errorf("failed to create session: %w", err)
-			}
-			session, err = s.LoadSession(ctx, channelID)
-			if err != nil {
-				return fmt.Errorf("failed to load session after creation: %w", err)
+// AddUserToSession adds a user to the jam session for a specific channel, or returns an error wrapping
+// ErrSessionNotFound if no session has been started there
+func (s *Service) AddUserToSession(ctx context.Context, guildID, channelID, userID string) error {
+	addUser := func(session *Session) error {
+		// Check if user is already in session
+		for _, id := range session.Participants {
+			if id == userID {
+				return ErrAlreadyInSession
 			}
-		} else {
-			return err
 		}
-	}
 
-	// Check if user is already in session
-	for _, id := range session.Participants {
-		if id == userID {
-			return fmt.Errorf("user is already in the session")
+		session.Participants = append(session.Participants, userID)
+		if session.GuildID == "" {
+			// Sessions saved before guilds were recorded pick theirs up from the next join
+			session.GuildID = guildID
 		}
+		return nil
 	}
 
-	session.Participants = append(session.Participants, userID)
-	return s.SaveSession(ctx, session)
+	_, err := s.sessions.UpdateSession(ctx, channelID, addUser)
+	return err
 }
 
-// RemoveUserFromSession removes a user from the jam session for a specific channel
+// RemoveUserFromSession removes a user from the jam session for a specific channel.
+// If the host leaves, the longest-standing remaining participant becomes the host.
 func (s *Service) RemoveUserFromSession(ctx context.Context, channelID, userID string) error {
-	session, err := s.LoadSession(ctx, channelID)
-	if err != nil {
-		return err
-	}
+	_, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		// Find and remove the user from participants
+		for i, id := range session.Participants {
+			if id == userID {
+				session.Participants = append(session.Participants[:i], session.Participants[i+1:]...)
+				break
+			}
+		}
 
-	// Find and remove the user from participants
-	for i, id := range session.Participants {
-		if id == userID {
-			session.Participants = append(session.Participants[:i], session.Participants[i+1:]...)
-			break
+		if session.Host == userID && len(session.Participants) > 0 {
+			session.Host = session.Participants[0]
 		}
-	}
+		return nil
+	})
 
-	return s.SaveSession(ctx, session)
+	return err
 }
 
 // GetSessionParticipants retrieves all users in the jam session for a specific channel
@@ -335,459 +339,408 @@ func (s *Service) GetSessionParticipants(ctx context.Context, channelID string)
 	return session.Participants, nil
 }
 
-// LoadAllSessions retrieves all active jam sessions from Redis
+// LoadAllSessions retrieves all active jam sessions
 func (s *Service) LoadAllSessions(ctx context.Context) ([]Session, error) {
-	var sessions []Session
-	iter := s.redisClient.Scan(ctx, 0, fmt.Sprintf("%s*", sessionKeyPrefix), 0).Iterator()
-	for iter.Next(ctx) {
-		key := iter.Val()
-		sessionData, err := s.redisClient.Get(ctx, key).Result()
-		if err != nil {
-			log.Printf("[ERROR] Failed to get session data for key %s: %v", key, err)
-			continue
-		}
-
-		var session Session
-		err = json.Unmarshal([]byte(sessionData), &session)
-		if err != nil {
-			log.Printf("[ERROR] Failed to unmarshal session data for key %s: %v", key, err)
-			continue
-		}
-
-		sessions = append(sessions, session)
-	}
-
-	if err := iter.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating Redis keys: %w", err)
-	}
-
-	return sessions, nil
+	return s.sessions.LoadAllSessions(ctx)
 }
 
-// AddSongToQueue adds a song to the session's queue
+// AddSongToQueue adds a song to the session's queue, or returns a *PolicyError naming the rule that blocked it
 func (s *Service) AddSongToQueue(ctx context.Context, channelID string, song Song) error {
-	session, err := s.LoadSession(ctx, channelID)
+	rejected, err := s.AddSongsToQueue(ctx, channelID, []Song{song})
 	if err != nil {
-		return fmt.Errorf("failed to load session: %w", err)
+		return err
 	}
-
-	session.Queue = append(session.Queue, song)
-	return s.SaveSession(ctx, session)
+	if len(rejected) > 0 {
+		return rejected[0]
+	}
+	return nil
 }
 
-// SearchSong searches for a song using Spotify API and returns the first result
-func (s *Service) SearchSong(ctx context.Context, userId, query string) (Song, error) {
-	client, err := s.GetClient(ctx, userId) // Using ChannelID to get the client
+// AddSongsToQueue adds several songs to the session's queue in one update, keeping their order.
+// Songs blocked by the guild's queue policy are left out and returned with the rule that blocked them.
+func (s *Service) AddSongsToQueue(ctx context.Context, channelID string, songs []Song) ([]*PolicyError, error) {
+	policy, err := s.sessionPolicy(ctx, channelID)
 	if err != nil {
-		return Song{}, fmt.Errorf("failed to get Spotify client: %w", err)
+		return nil, fmt.Errorf("failed to update session: %w", err)
 	}
 
-	searchURL := "https://api.spotify.com/v1/search"
-	params := url.Values{}
-	params.Add("q", query)
-	params.Add("type", "track")
-	params.Add("limit", "1")
-
-	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s?%s", searchURL, params.Encode()), nil)
+	var rejected []*PolicyError
+	_, err = s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		rejected = nil
+		for _, song := range songs {
+			if blocked := policy.check(session, song); blocked != nil {
+				rejected = append(rejected, blocked)
+				continue
+			}
+			session.enqueue(song)
+		}
+		return nil
+	})
 	if err != nil {
-		return Song{}, fmt.Errorf("failed to create search request: %w", err)
+		return nil, fmt.Errorf("failed to update session: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	return rejected, nil
+}
+
+// SearchSong searches for a song using Spotify API and returns the first result
+func (s *Service) SearchSong(ctx context.Context, userId, query string) (Song, error) {
+	songs, err := s.SearchSongs(ctx, userId, query, 1)
 	if err != nil {
-		return Song{}, fmt.Errorf("search request failed: %w", err)
+		return Song{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := ioutil.ReadAll(resp.Body)
-		return Song{}, fmt.Errorf("search request returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	if len(songs) == 0 {
+		return Song{}, fmt.Errorf("no results found for query: %s", query)
 	}
 
-	var searchResult struct {
-		Tracks struct {
-			Items []struct {
-				Name    string `json:"name"`
-				Artists []struct {
-					Name string `json:"name"`
-				} `json:"artists"`
-				URI string `json:"uri"`
-			} `json:"items"`
-		} `json:"tracks"`
+	return songs[0], nil
+}
+
+// SearchSongs searches for songs using Spotify API and returns up to limit results, best match first
+func (s *Service) SearchSongs(ctx context.Context, userId, query string, limit int) ([]Song, error) {
+	client, err := s.apiClient(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Spotify client: %w", err)
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&searchResult)
+	tracks, err := client.SearchTracks(ctx, query, limit)
 	if err != nil {
-		return Song{}, fmt.Errorf("failed to decode search response: %w", err)
+		return nil, fmt.Errorf("search request failed: %w", err)
 	}
 
-	if len(searchResult.Tracks.Items) == 0 {
-		return Song{}, fmt.Errorf("no results found for query: %s", query)
+	songs := make([]Song, 0, len(tracks))
+	for _, track := range tracks {
+		songs = append(songs, songFromTrack(track))
 	}
+	return songs, nil
+}
 
-	firstTrack := searchResult.Tracks.Items[0]
+// songFromTrack converts a Spotify API track into a queueable song
+func songFromTrack(track api.Track) Song {
 	artistNames := []string{}
-	for _, artist := range firstTrack.Artists {
+	for _, artist := range track.Artists {
 		artistNames = append(artistNames, artist.Name)
 	}
 
-	return Song{
-		Title:  firstTrack.Name,
-		Artist: strings.Join(artistNames, ", "),
-		URI:    firstTrack.URI,
-	}, nil
+	song := Song{
+		Title:       track.Name,
+		Artist:      strings.Join(artistNames, ", "),
+		URI:         track.URI,
+		DurationMs:  track.DurationMs,
+		Album:       track.Album.Name,
+		Explicit:    track.Explicit,
+		Popularity:  track.Popularity,
+		ExternalURL: track.ExternalURLs.Spotify,
+	}
+	if len(track.Album.Images) > 0 {
+		// Spotify lists the widest image first
+		song.ArtworkURL = track.Album.Images[0].URL
+	}
+	return song
 }
 
 // RemoveSongFromQueue removes a song from the session's queue at the specified index
 func (s *Service) RemoveSongFromQueue(ctx context.Context, channelID string, index int) error {
-	session, err := s.LoadSession(ctx, channelID)
-	if err != nil {
-		return fmt.Errorf("failed to load session: %w", err)
-	}
+	_, err := s.RemoveSongRange(ctx, channelID, index, index)
+	return err
+}
 
-	if index < 0 || index >= len(session.Queue) {
-		return fmt.Errorf("song position out of range")
+// popCurrent moves the song at the head of the queue into the play history and resets playback.
+// With repeat-queue on, the song also goes back into the queue as a newly added song.
+func (session *Session) popCurrent() {
+	finished := session.Queue[0]
+	session.PlayedCount++
+	session.History = append(session.History, finished)
+	if len(session.History) > maxHistoryLength {
+		session.History = session.History[len(session.History)-maxHistoryLength:]
 	}
 
-	removedSong := session.Queue[index]
-	session.Queue = append(session.Queue[:index], session.Queue[index+1:]...)
-
-	// If the removed song is currently playing, reset playback state
-	if session.Playback.IsPlaying && session.Playback.CurrentSong.URI == removedSong.URI {
-		session.Playback.IsPlaying = false
-		session.Playback.PositionMs = 0       // Reset position
-		session.Playback.CurrentSong = Song{} // Clear current song
-	}
+	session.popHead()
+}
 
-	err = s.SaveSession(ctx, session)
-	if err != nil {
-		return fmt.Errorf("failed to save session: %w", err)
+// popHead takes the song at the head of the queue off without recording it as played and resets playback.
+// With repeat-queue on, the song goes back into the queue as a newly added song.
+func (session *Session) popHead() {
+	song := session.Queue[0]
+	session.Queue = session.Queue[1:]
+	if session.RepeatMode() == RepeatQueue {
+		session.insertUpcoming(session.stamp(song))
 	}
-
-	return nil
+	session.Playback = PlaybackState{}
+	session.Playback.Reset(time.Now())
 }
 
-// ///////////////////////
-// //////////////////////
-// PlayRequest represents the payload for the Spotify Play API
-type PlayRequest struct {
-	URIs       []string `json:"uris,omitempty"`
-	PositionMs int      `json:"position_ms,omitempty"`
+// SkipTrack moves on to the next song in the queue and starts it for all participants.
+// Skipping the last song finishes the queue and pauses everyone instead, and skipping while
+// paused leaves the next song waiting for !play.
+func (s *Service) SkipTrack(ctx context.Context, channelID string) (PlaybackReport, error) {
+	return s.skipSong(ctx, channelID, "")
 }
 
-// Device represents a Spotify device
-type Device struct {
-	ID            string `json:"id"`
-	IsActive      bool   `json:"is_active"`
-	IsRestricted  bool   `json:"is_restricted"`
-	Name          string `json:"name"`
-	Type          string `json:"type"`
-	VolumePercent int    `json:"volume_percent"`
-}
+// skipSong skips the song at the head of the queue. With songURI set, it returns errStaleVote
+// instead if that song is no longer the current one, so racing skips can't skip twice.
+func (s *Service) skipSong(ctx context.Context, channelID, songURI string) (PlaybackReport, error) {
+	var wasPlaying bool
 
-// DevicesResponse represents the response from the Spotify Devices API
-type DevicesResponse struct {
-	Devices []Device `json:"devices"`
-}
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		if songURI != "" && session.Playback.CurrentSong.URI != songURI {
+			return errStaleVote
+		}
+		if songURI != "" && (len(session.Queue) == 0 || session.Queue[0].URI != songURI) {
+			return errStaleVote
+		}
+		if len(session.Queue) == 0 {
+			return fmt.Errorf("the queue is empty")
+		}
 
-// StartPlayback starts playback of the queued songs for all participants in the channel's session
-func (s *Service) StartPlayback(ctx context.Context, channelID string) error {
-	session, err := s.LoadSession(ctx, channelID)
+		wasPlaying = session.Playback.IsPlaying
+		if session.hasCurrent() {
+			session.popCurrent()
+		} else {
+			// The song never started, so it doesn't count as played
+			session.popHead()
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to load session: %w", err)
+		return PlaybackReport{}, err
 	}
 
-	if len(session.Queue) == 0 {
-		return fmt.Errorf("the queue is empty")
+	if len(session.Queue) > 0 && wasPlaying {
+		return s.StartPlayback(ctx, channelID)
+	}
+	if len(session.Queue) > 0 {
+		// Stay paused; the next song starts with the next !play
+		s.scheduler.Cancel(channelID)
+		s.notifyPlaybackChange(session)
+		return PlaybackReport{Song: session.Queue[0], Paused: true}, nil
 	}
 
-	currentSong := session.Queue[0]
+	// Nothing left to play; stop everyone on the skipped song
+	log.Printf("[INFO] Queue finished for channel %s", channelID)
+	s.scheduler.Cancel(channelID)
+	s.syncer.Stop(channelID)
 
-	// Reset position if starting a new song
-	if session.Playback.CurrentSong.URI != currentSong.URI {
-		session.Playback.PositionMs = 0
-		session.Playback.CurrentSong = currentSong
-	} else if session.Playback.IsPlaying {
-		// Only update position if it's the same song and was playing
-		elapsed := time.Now().Unix() - session.Playback.LastUpdatedAt
-		session.Playback.PositionMs += int(elapsed * 1000)
+	report := PlaybackReport{Finished: true}
+	if wasPlaying {
+		report.Results = s.fanOut(ctx, session.Participants, func(ctx context.Context, client *api.Client, device api.Device) error {
+			return client.Pause(ctx, device.ID)
+		})
+		s.notifyFailures(report.Results, "pause playback")
 	}
 
-	// Iterate over each participant and start playback
-	for _, userID := range session.Participants {
-		client, err := s.GetClient(ctx, userID)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to start playback: %v", err))
-			continue
-		}
+	s.notifyPlaybackChange(session)
 
-		// Retrieve user's active devices
-		devices, err := s.GetUserDevices(ctx, client)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Unable to retrieve devices: %v", err))
-			continue
-		}
+	return report, nil
+}
 
-		// Select the first available active device
-		if len(devices) == 0 {
-			s.SendDM(userID, "❌ No active Spotify devices found. Please open Spotify on one of your devices.")
-			continue
+// PreviousTrack puts the most recently played song back at the head of the queue and starts it for all participants
+func (s *Service) PreviousTrack(ctx context.Context, channelID string) (PlaybackReport, error) {
+	_, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		if len(session.History) == 0 {
+			return fmt.Errorf("there is no previous song to go back to")
 		}
 
-		deviceID := devices[0].ID // Selecting the first device
+		last := len(session.History) - 1
+		previous := session.History[last]
+		session.History = session.History[:last]
+		session.Queue = append([]Song{previous}, session.Queue...)
+		session.Playback = PlaybackState{}
+		session.Playback.Reset(time.Now())
+		return nil
+	})
+	if err != nil {
+		return PlaybackReport{}, err
+	}
 
-		// Prepare the playback request with position_ms
-		playReq := &PlayRequest{
-			URIs:       []string{currentSong.URI},
-			PositionMs: session.Playback.PositionMs,
-		}
+	return s.StartPlayback(ctx, channelID)
+}
 
-		playReqBody, err := json.Marshal(playReq)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to marshal playback request: %v", err))
-			continue
-		}
+// StartPlayback starts playback of the queued songs for all participants in the channel's session
+func (s *Service) StartPlayback(ctx context.Context, channelID string) (PlaybackReport, error) {
+	var currentSong Song
+	var newSong bool
 
-		// Create the playback API request
-		playURL := "https://api.spotify.com/v1/me/player/play?device_id=" + deviceID
-		req, err := http.NewRequestWithContext(ctx, "PUT", playURL, strings.NewReader(string(playReqBody)))
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to create playback request: %v", err))
-			continue
+	// Update the session's playback state
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		if len(session.Queue) == 0 {
+			return fmt.Errorf("the queue is empty")
 		}
-		req.Header.Set("Content-Type", "application/json")
 
-		// Execute the playback request
-		resp, err := client.Do(req)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Playback request failed: %v", err))
-			continue
-		}
-		defer resp.Body.Close()
+		currentSong = session.Queue[0]
 
-		if resp.StatusCode != http.StatusNoContent {
-			bodyBytes, _ := ioutil.ReadAll(resp.Body)
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to start playback (Status %d): %s", resp.StatusCode, string(bodyBytes)))
-			continue
+		now := time.Now()
+
+		// Reset position if starting a new song; otherwise resume from where the clock is
+		newSong = session.Playback.CurrentSong.URI != currentSong.URI
+		if newSong {
+			session.Playback.Reset(now)
 		}
 
-		// Optionally, notify the user of successful playback start
-		s.SendDM(userID, fmt.Sprintf("✅ Now playing **%s** by **%s** from %d ms.", currentSong.Title, currentSong.Artist, session.Playback.PositionMs))
+		session.Playback.Play(now)
+		session.Playback.CurrentSong = currentSong
+		return nil
+	})
+	if err != nil {
+		return PlaybackReport{}, err
 	}
 
-	// Update the session's playback state
-	session.Playback.IsPlaying = true
-	session.Playback.LastUpdatedAt = time.Now().Unix()
-	session.Playback.CurrentSong = currentSong
-
-	err = s.SaveSession(ctx, session)
-	if err != nil {
-		return fmt.Errorf("failed to save session: %w", err)
+	if newSong {
+		// Votes cast the last time this song played must not carry over
+		err = s.ClearSkipVotes(ctx, channelID, currentSong.URI)
+		if err != nil {
+			log.Printf("[WARN] Failed to clear skip votes for channel %s: %v", channelID, err)
+		}
 	}
 
-	// quit := make(chan struct{})
-	// s.StartSyncTicker(channelID, 10*time.Second, quit)
+	// Start the current song from the session position on every participant's device at once
+	report := PlaybackReport{Song: currentSong, PositionMs: session.Playback.PositionMs}
+	report.Results = s.fanOut(ctx, session.Participants, func(ctx context.Context, client *api.Client, device api.Device) error {
+		return client.Play(ctx, device.ID, api.PlayOptions{
+			URIs:       []string{currentSong.URI},
+			PositionMs: report.PositionMs,
+		})
+	})
+	s.notifyFailures(report.Results, "start playback")
+
+	// Arm the timer that moves on to the next song once this one finishes
+	s.scheduler.Schedule(session)
+	// Keep listeners in step while the session plays
+	s.syncer.Start(channelID)
+	s.notifyPlaybackChange(session)
 
-	return nil
+	return report, nil
 }
 
 // PausePlayback pauses the current playback for all participants in the channel's session
-func (s *Service) PausePlayback(ctx context.Context, channelID string) error {
-	session, err := s.LoadSession(ctx, channelID)
+func (s *Service) PausePlayback(ctx context.Context, channelID string) (PlaybackReport, error) {
+	// Update the session's playback state
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		if !session.Playback.IsPlaying {
+			return fmt.Errorf("playback is already paused")
+		}
+
+		// Stop the clock at the current playback position
+		session.Playback.Pause(time.Now())
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to load session: %w", err)
+		return PlaybackReport{}, err
 	}
 
-	if !session.Playback.IsPlaying {
-		return fmt.Errorf("playback is already paused")
-	}
+	// Nothing should advance or be synchronized while the session is paused
+	s.scheduler.Cancel(channelID)
+	s.syncer.Stop(channelID)
 
-	// Calculate the current playback position
-	elapsed := time.Now().Unix() - session.Playback.LastUpdatedAt
-	session.Playback.PositionMs += int(elapsed * 1000) // Convert seconds to milliseconds
+	report := PlaybackReport{Song: session.Playback.CurrentSong, PositionMs: session.Playback.PositionMs}
+	report.Results = s.fanOut(ctx, session.Participants, func(ctx context.Context, client *api.Client, device api.Device) error {
+		return client.Pause(ctx, device.ID)
+	})
+	s.notifyFailures(report.Results, "pause playback")
 
-	// Iterate over each participant and pause playback
-	for _, userID := range session.Participants {
-		client, err := s.GetClient(ctx, userID)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to pause playback: %v", err))
-			continue
-		}
+	s.notifyPlaybackChange(session)
 
-		// Retrieve user's active devices
-		devices, err := s.GetUserDevices(ctx, client)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Unable to retrieve devices: %v", err))
-			continue
-		}
+	return report, nil
+}
 
-		// Select the first available active device
-		if len(devices) == 0 {
-			s.SendDM(userID, "❌ No active Spotify devices found. Please open Spotify on one of your devices.")
-			continue
+// SeekPlayback moves the current song to a position for all participants in the channel's session.
+// With relative set, positionMs is an offset from the current position; the result is clamped at the start of the song.
+func (s *Service) SeekPlayback(ctx context.Context, channelID string, positionMs int, relative bool) (PlaybackReport, error) {
+	session, err := s.sessions.UpdateSession(ctx, channelID, func(session *Session) error {
+		song := session.Playback.CurrentSong
+		if song.URI == "" {
+			return fmt.Errorf("nothing is playing right now")
 		}
 
-		deviceID := devices[0].ID // Selecting the first device
-
-		// Create the pause API request
-		pauseURL := "https://api.spotify.com/v1/me/player/pause?device_id=" + deviceID
-		req, err := http.NewRequestWithContext(ctx, "PUT", pauseURL, nil)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to create pause request: %v", err))
-			continue
+		now := time.Now()
+		target := positionMs
+		if relative {
+			target += session.Playback.PositionAt(now)
 		}
-
-		// Execute the pause request
-		resp, err := client.Do(req)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Pause request failed: %v", err))
-			continue
+		if target < 0 {
+			target = 0
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusNoContent {
-			bodyBytes, _ := ioutil.ReadAll(resp.Body)
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to pause playback (Status %d): %s", resp.StatusCode, string(bodyBytes)))
-			continue
+		// Songs queued before durations were recorded can't be checked
+		if song.DurationMs > 0 && target >= song.DurationMs {
+			return ErrSeekOutOfRange
 		}
 
-		// Optionally, notify the user of successful pause
-		s.SendDM(userID, "✅ Playback has been paused.")
-	}
-
-	// Update the session's playback state
-	session.Playback.IsPlaying = false
-	session.Playback.LastUpdatedAt = time.Now().Unix()
-
-	err = s.SaveSession(ctx, session)
+		session.Playback.Seek(target, now)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to save session: %w", err)
+		return PlaybackReport{}, err
 	}
 
-	return nil
-}
+	report := PlaybackReport{Song: session.Playback.CurrentSong, PositionMs: session.Playback.PositionMs}
 
-// GetUserDevices retrieves the available devices for a user
-func (s *Service) GetUserDevices(ctx context.Context, client *http.Client) ([]Device, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.spotify.com/v1/me/player/devices", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create devices request: %w", err)
+	// A paused session picks up the new position when playback resumes
+	if session.Playback.IsPlaying {
+		report.Results = s.fanOut(ctx, session.Participants, func(ctx context.Context, client *api.Client, device api.Device) error {
+			return client.Seek(ctx, device.ID, report.PositionMs)
+		})
+		s.notifyFailures(report.Results, "seek")
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("devices request failed: %w", err)
-	}
-	defer resp.Body.Close()
+	// The song now ends at a different time
+	s.scheduler.Schedule(session)
+	s.notifyPlaybackChange(session)
+
+	return report, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("devices request returned status %d: %s", resp.StatusCode, string(bodyBytes))
+// notifyPlaybackChange reports a session's new playback state to the OnPlaybackChange hook, if set
+func (s *Service) notifyPlaybackChange(session *Session) {
+	if s.OnPlaybackChange == nil {
+		return
 	}
 
-	var devicesResp DevicesResponse
-	err = json.NewDecoder(resp.Body).Decode(&devicesResp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode devices response: %w", err)
-	}
-
-	return devicesResp.Devices, nil
-}
-
-// StartSyncTicker initiates a ticker to periodically synchronize playback positions
-func (s *Service) StartSyncTicker(channelID string, interval time.Duration, quit <-chan struct{}) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				err := s.SynchronizePlayback(channelID)
-				if err != nil {
-					fmt.Printf("Failed to synchronize playback for channel %s: %v\n", channelID, err)
-				}
-			case <-quit:
-				ticker.Stop()
-				return
-			}
-		}
-	}()
+	// The hook talks to Discord; don't hold up the playback path
+	snapshot := *session
+	go s.OnPlaybackChange(&snapshot)
 }
 
-// SynchronizePlayback ensures all users are playing the current song from the same position
-func (s *Service) SynchronizePlayback(channelID string) error {
-	session, err := s.LoadSession(context.Background(), channelID)
+// apiClient returns a Spotify Web API client authenticated as a Discord user
+func (s *Service) apiClient(ctx context.Context, discordUserID string) (*api.Client, error) {
+	httpClient, err := s.GetClient(ctx, discordUserID)
 	if err != nil {
-		return fmt.Errorf("failed to load session: %w", err)
-	}
-
-	if !session.Playback.IsPlaying {
-		return nil // No need to synchronize if not playing
+		return nil, err
 	}
+	return api.NewClient(httpClient, s.apiBaseURL), nil
+}
 
-	// Calculate the current playback position
-	elapsed := time.Now().Unix() - session.Playback.LastUpdatedAt
-	currentPosition := session.Playback.PositionMs + int(elapsed*1000)
-
-	// Iterate over each participant and update playback position if necessary
-	for _, userID := range session.Participants {
-		client, err := s.GetClient(context.Background(), userID)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to synchronize playback: %v", err))
-			continue
-		}
-
-		// Retrieve user's active devices
-		devices, err := s.GetUserDevices(context.Background(), client)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Unable to retrieve devices for synchronization: %v", err))
-			continue
-		}
-
-		if len(devices) == 0 {
-			s.SendDM(userID, "❌ No active Spotify devices found for synchronization.")
-			continue
-		}
-
-		deviceID := devices[0].ID // Selecting the first device
-
-		// Prepare the playback request with synchronized position
-		playReq := &PlayRequest{
-			URIs:       []string{session.Playback.CurrentSong.URI},
-			PositionMs: currentPosition,
-		}
-
-		playReqBody, err := json.Marshal(playReq)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to marshal synchronization playback request: %v", err))
-			continue
-		}
-
-		// Create the playback API request
-		playURL := "https://api.spotify.com/v1/me/player/play?device_id=" + deviceID
-		req, err := http.NewRequestWithContext(context.Background(), "PUT", playURL, strings.NewReader(string(playReqBody)))
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to create synchronization playback request: %v", err))
-			continue
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		// Execute the playback request
-		resp, err := client.Do(req)
-		if err != nil {
-			s.SendDM(userID, fmt.Sprintf("❌ Synchronization playback request failed: %v", err))
-			continue
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusNoContent {
-			bodyBytes, _ := ioutil.ReadAll(resp.Body)
-			s.SendDM(userID, fmt.Sprintf("❌ Failed to synchronize playback (Status %d): %s", resp.StatusCode, string(bodyBytes)))
-			continue
-		}
-
-		// Optionally, notify the user of successful synchronization
-		s.SendDM(userID, fmt.Sprintf("🔄 Synchronized playback to **%s** at %d ms.", session.Playback.CurrentSong.Title, currentPosition))
+// describeAPIError turns Spotify API failures into advice a user can act on
+func describeAPIError(err error) string {
+	var apiErr *api.Error
+	isAPIErr := errors.As(err, &apiErr)
+
+	switch {
+	case errors.Is(err, ErrTokenNotFound):
+		return "You are not connected to Spotify. Use `!auth` to connect."
+	case errors.Is(err, errNotFollowing):
+		return "You are not playing the jam session's song."
+	case errors.Is(err, errNoDevices):
+		return "No Spotify devices found. Please open Spotify on one of your devices."
+	case errors.Is(err, errRestrictedDevices):
+		return "Your Spotify devices can't be controlled by the bot. Please open Spotify on another device."
+	case errors.Is(err, context.DeadlineExceeded):
+		return "Spotify took too long to respond."
+	case errors.Is(err, api.ErrPremiumRequired):
+		return "Spotify Premium is required to control playback."
+	case errors.Is(err, api.ErrNoActiveDevice):
+		return "No active Spotify device found. Please open Spotify on one of your devices."
+	case errors.Is(err, api.ErrUnauthorized):
+		return "Your Spotify login has expired. Use `!auth` to reconnect."
+	case errors.Is(err, api.ErrRateLimited):
+		if isAPIErr && apiErr.RetryAfter > 0 {
+			return fmt.Sprintf("Spotify is rate limiting requests. Please try again in %s.", apiErr.RetryAfter.Round(time.Second))
+		}
+		return "Spotify is rate limiting requests. Please try again in a moment."
+	case isAPIErr && apiErr.Temporary():
+		return "Spotify is having trouble right now. Please try again in a moment."
+	default:
+		return err.Error()
 	}
-
-	return nil
 }
@@ -0,0 +1,604 @@
+// Package spotifytest provides an in-process fake of the Spotify Web API and accounts service,
+// so the playback flow can be exercised end to end without network access.
+package spotifytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"jam-bot/internal/config"
+)
+
+// Track is a song in the fake catalog
+type Track struct {
+	Name       string
+	Artists    []string
+	URI        string
+	DurationMs int
+	Album      string
+	ArtworkURL string
+	Explicit   bool
+	Popularity int
+}
+
+// Collection is an album or playlist in the fake catalog
+type Collection struct {
+	ID     string
+	Name   string
+	Tracks []Track
+}
+
+// Device is a Spotify device belonging to a fake user
+type Device struct {
+	ID            string `json:"id"`
+	IsActive      bool   `json:"is_active"`
+	IsRestricted  bool   `json:"is_restricted"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	VolumePercent int    `json:"volume_percent"`
+}
+
+// PlayerState is what a fake user's Spotify client is doing
+type PlayerState struct {
+	DeviceID   string
+	TrackURI   string
+	PositionMs int
+	IsPlaying  bool
+	UpdatedAt  time.Time
+	PlayCalls  int // Number of play requests received
+	PauseCalls int // Number of pause requests received
+	SeekCalls  int // Number of seek requests received
+}
+
+// Progress returns the player position at the given time
+func (p PlayerState) Progress(now time.Time) int {
+	if !p.IsPlaying {
+		return p.PositionMs
+	}
+	return p.PositionMs + int(now.Sub(p.UpdatedAt).Milliseconds())
+}
+
+// user is a fake Spotify account
+type user struct {
+	devices []Device
+	player  PlayerState
+}
+
+// Server is a fake Spotify API. The authorization code exchanged for a token names the user it belongs to,
+// so exchanging code "alice" logs in as user "alice".
+type Server struct {
+	*httptest.Server
+
+	// TokenLifetime is how long issued access tokens are valid for
+	TokenLifetime time.Duration
+
+	mu            sync.Mutex
+	tracks        []Track
+	albums        map[string]Collection
+	playlists     map[string]Collection
+	users         map[string]*user
+	accessTokens  map[string]string // access token -> user
+	refreshTokens map[string]string // refresh token -> user
+	issued        int
+	faults        []fault
+	requests      int
+}
+
+// fault is a canned error response for an upcoming Web API request
+type fault struct {
+	status     int
+	retryAfter time.Duration
+}
+
+// NewServer starts a fake Spotify server with an empty catalog. Call Close when done.
+func NewServer() *Server {
+	srv := &Server{
+		TokenLifetime: time.Hour,
+		users:         make(map[string]*user),
+		albums:        make(map[string]Collection),
+		playlists:     make(map[string]Collection),
+		accessTokens:  make(map[string]string),
+		refreshTokens: make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/token", srv.handleToken)
+	mux.HandleFunc("/v1/search", srv.withUser(srv.handleSearch))
+	mux.HandleFunc("/v1/tracks", srv.withUser(srv.handleTracks))
+	mux.HandleFunc("/v1/tracks/", srv.withUser(srv.handleTrack))
+	mux.HandleFunc("/v1/albums/", srv.withUser(srv.handleCollection("/v1/albums/", "album")))
+	mux.HandleFunc("/v1/playlists/", srv.withUser(srv.handleCollection("/v1/playlists/", "playlist")))
+	mux.HandleFunc("/v1/me/player", srv.withUser(srv.handlePlayer))
+	mux.HandleFunc("/v1/me/player/devices", srv.withUser(srv.handleDevices))
+	mux.HandleFunc("/v1/me/player/play", srv.withUser(srv.handlePlay))
+	mux.HandleFunc("/v1/me/player/pause", srv.withUser(srv.handlePause))
+	mux.HandleFunc("/v1/me/player/seek", srv.withUser(srv.handleSeek))
+	srv.Server = httptest.NewServer(mux)
+
+	return srv
+}
+
+// APIBaseURL returns the base URL to use in place of https://api.spotify.com/v1
+func (srv *Server) APIBaseURL() string {
+	return srv.URL + "/v1"
+}
+
+// AccountsBaseURL returns the base URL to use in place of https://accounts.spotify.com
+func (srv *Server) AccountsBaseURL() string {
+	return srv.URL
+}
+
+// Configure points a bot configuration at the fake server
+func (srv *Server) Configure(cfg *config.Config) {
+	cfg.SpotifyAPIBaseURL = srv.APIBaseURL()
+	cfg.SpotifyAccountsBaseURL = srv.AccountsBaseURL()
+}
+
+// AddTracks adds songs to the searchable catalog
+func (srv *Server) AddTracks(tracks ...Track) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.tracks = append(srv.tracks, tracks...)
+}
+
+// AddAlbum adds an album, and its tracks to the searchable catalog
+func (srv *Server) AddAlbum(album Collection) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.albums[album.ID] = album
+	srv.tracks = append(srv.tracks, album.Tracks...)
+}
+
+// AddPlaylist adds a playlist; its tracks are not added to the searchable catalog
+func (srv *Server) AddPlaylist(playlist Collection) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.playlists[playlist.ID] = playlist
+}
+
+// SetDevices replaces a user's devices. Users start with a single active desktop device.
+func (srv *Server) SetDevices(userName string, devices ...Device) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.userLocked(userName).devices = devices
+}
+
+// FailNext makes the next n Web API requests fail with status. A non-zero retryAfter is sent as Retry-After.
+func (srv *Server) FailNext(n int, status int, retryAfter time.Duration) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		srv.faults = append(srv.faults, fault{status: status, retryAfter: retryAfter})
+	}
+}
+
+// Requests returns how many Web API requests the server has received, including failed ones
+func (srv *Server) Requests() int {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	return srv.requests
+}
+
+// Player returns a snapshot of a user's player state
+func (srv *Server) Player(userName string) PlayerState {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	return srv.userLocked(userName).player
+}
+
+// Drift moves a user's playback position by offsetMs, as if their client had fallen behind or run ahead
+func (srv *Server) Drift(userName string, offsetMs int) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	player := &srv.userLocked(userName).player
+	player.PositionMs = player.Progress(time.Now()) + offsetMs
+	player.UpdatedAt = time.Now()
+}
+
+// userLocked returns a user, creating it with a default device; srv.mu must be held
+func (srv *Server) userLocked(userName string) *user {
+	u, ok := srv.users[userName]
+	if !ok {
+		u = &user{
+			devices: []Device{{
+				ID:            userName + "-desktop",
+				IsActive:      true,
+				Name:          userName + "'s Desktop",
+				Type:          "Computer",
+				VolumePercent: 100,
+			}},
+		}
+		srv.users[userName] = u
+	}
+	return u
+}
+
+// handleToken implements the authorization_code and refresh_token grants
+func (srv *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form")
+		return
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	var userName string
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		userName = r.PostForm.Get("code")
+		if userName == "" {
+			writeOAuthError(w, "invalid_grant", "missing code")
+			return
+		}
+		srv.userLocked(userName)
+	case "refresh_token":
+		var ok bool
+		userName, ok = srv.refreshTokens[r.PostForm.Get("refresh_token")]
+		if !ok {
+			writeOAuthError(w, "invalid_grant", "invalid refresh token")
+			return
+		}
+	default:
+		writeOAuthError(w, "unsupported_grant_type", "unsupported grant type")
+		return
+	}
+
+	srv.issued++
+	accessToken := fmt.Sprintf("access-%s-%d", userName, srv.issued)
+	refreshToken := fmt.Sprintf("refresh-%s-%d", userName, srv.issued)
+	srv.accessTokens[accessToken] = userName
+	srv.refreshTokens[refreshToken] = userName
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  accessToken,
+		"token_type":    "Bearer",
+		"refresh_token": refreshToken,
+		"expires_in":    int(srv.TokenLifetime.Seconds()),
+		"scope":         "user-read-playback-state user-modify-playback-state user-read-currently-playing",
+	})
+}
+
+// withUser resolves the bearer token to a user before calling the handler with srv.mu held
+func (srv *Server) withUser(handler func(w http.ResponseWriter, r *http.Request, u *user)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+
+		srv.requests++
+		if len(srv.faults) > 0 {
+			f := srv.faults[0]
+			srv.faults = srv.faults[1:]
+			if f.retryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(f.retryAfter.Seconds())))
+			}
+			writeError(w, f.status, http.StatusText(f.status))
+			return
+		}
+
+		userName, ok := srv.accessTokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "Invalid access token")
+			return
+		}
+
+		handler(w, r, srv.userLocked(userName))
+	}
+}
+
+func (srv *Server) handleSearch(w http.ResponseWriter, r *http.Request, u *user) {
+	query := strings.ToLower(r.URL.Query().Get("q"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	items := []map[string]interface{}{}
+	for _, track := range srv.tracks {
+		if len(items) == limit {
+			break
+		}
+		haystack := strings.ToLower(track.Name + " " + strings.Join(track.Artists, " "))
+		if !strings.Contains(haystack, query) {
+			continue
+		}
+		items = append(items, trackJSON(track))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tracks": map[string]interface{}{
+			"items": items,
+			"limit": limit,
+			"total": len(items),
+		},
+	})
+}
+
+func (srv *Server) handleTrack(w http.ResponseWriter, r *http.Request, u *user) {
+	track, ok := srv.trackLocked(strings.TrimPrefix(r.URL.Path, "/v1/tracks/"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Non existing id")
+		return
+	}
+	writeJSON(w, http.StatusOK, trackJSON(track))
+}
+
+// handleTracks looks up several tracks; unknown IDs come back as null, as they do from Spotify
+func (srv *Server) handleTracks(w http.ResponseWriter, r *http.Request, u *user) {
+	items := []interface{}{}
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		track, ok := srv.trackLocked(id)
+		if !ok {
+			items = append(items, nil)
+			continue
+		}
+		items = append(items, trackJSON(track))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tracks": items})
+}
+
+// trackLocked finds a catalog track by ID; srv.mu must be held
+func (srv *Server) trackLocked(id string) (Track, bool) {
+	for _, track := range srv.tracks {
+		if track.URI == "spotify:track:"+id {
+			return track, true
+		}
+	}
+	return Track{}, false
+}
+
+// handleCollection serves an album or playlist at prefix+id and its tracks at prefix+id+"/tracks"
+func (srv *Server) handleCollection(prefix, kind string) func(w http.ResponseWriter, r *http.Request, u *user) {
+	return func(w http.ResponseWriter, r *http.Request, u *user) {
+		id, listTracks := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, prefix), "/tracks")
+
+		collections := srv.albums
+		if kind == "playlist" {
+			collections = srv.playlists
+		}
+		collection, ok := collections[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Resource not found")
+			return
+		}
+
+		if !listTracks {
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"id":     collection.ID,
+				"name":   collection.Name,
+				"uri":    "spotify:" + kind + ":" + collection.ID,
+				"tracks": map[string]interface{}{"total": len(collection.Tracks)},
+			})
+			return
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = 20
+		}
+		end := offset + limit
+		if end > len(collection.Tracks) {
+			end = len(collection.Tracks)
+		}
+
+		items := []interface{}{}
+		for i := offset; i < end; i++ {
+			item := trackJSON(collection.Tracks[i])
+			if kind == "playlist" {
+				// Playlist items wrap the track
+				items = append(items, map[string]interface{}{"is_local": false, "track": item})
+				continue
+			}
+			items = append(items, item)
+		}
+
+		next := ""
+		if end < len(collection.Tracks) {
+			next = fmt.Sprintf("%s%s%s/tracks?offset=%d&limit=%d", srv.URL, prefix, id, end, limit)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"items": items,
+			"next":  next,
+			"total": len(collection.Tracks),
+		})
+	}
+}
+
+func (srv *Server) handleDevices(w http.ResponseWriter, r *http.Request, u *user) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"devices": u.devices,
+	})
+}
+
+func (srv *Server) handlePlayer(w http.ResponseWriter, r *http.Request, u *user) {
+	if u.player.TrackURI == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var item map[string]interface{}
+	for _, track := range srv.tracks {
+		if track.URI == u.player.TrackURI {
+			item = trackJSON(track)
+			break
+		}
+	}
+	if item == nil {
+		item = map[string]interface{}{"uri": u.player.TrackURI}
+	}
+
+	var device *Device
+	for i := range u.devices {
+		if u.devices[i].ID == u.player.DeviceID {
+			device = &u.devices[i]
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"device":      device,
+		"progress_ms": u.player.Progress(time.Now()),
+		"is_playing":  u.player.IsPlaying,
+		"timestamp":   time.Now().UnixMilli(),
+		"item":        item,
+	})
+}
+
+func (srv *Server) handlePlay(w http.ResponseWriter, r *http.Request, u *user) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	device, ok := resolveDevice(u, r.URL.Query().Get("device_id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Device not found")
+		return
+	}
+
+	var body struct {
+		URIs       []string `json:"uris"`
+		PositionMs int      `json:"position_ms"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "Malformed json")
+			return
+		}
+	}
+
+	u.player.PlayCalls++
+	u.player.DeviceID = device
+	if len(body.URIs) > 0 {
+		u.player.TrackURI = body.URIs[0]
+		u.player.PositionMs = body.PositionMs
+	} else {
+		// Resume where the player left off
+		u.player.PositionMs = u.player.Progress(time.Now())
+	}
+	u.player.IsPlaying = true
+	u.player.UpdatedAt = time.Now()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (srv *Server) handlePause(w http.ResponseWriter, r *http.Request, u *user) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if _, ok := resolveDevice(u, r.URL.Query().Get("device_id")); !ok {
+		writeError(w, http.StatusNotFound, "Device not found")
+		return
+	}
+
+	u.player.PauseCalls++
+	u.player.PositionMs = u.player.Progress(time.Now())
+	u.player.IsPlaying = false
+	u.player.UpdatedAt = time.Now()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (srv *Server) handleSeek(w http.ResponseWriter, r *http.Request, u *user) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if _, ok := resolveDevice(u, r.URL.Query().Get("device_id")); !ok {
+		writeError(w, http.StatusNotFound, "Device not found")
+		return
+	}
+
+	position, err := strconv.Atoi(r.URL.Query().Get("position_ms"))
+	if err != nil || position < 0 {
+		writeError(w, http.StatusBadRequest, "Invalid position_ms")
+		return
+	}
+
+	u.player.SeekCalls++
+	u.player.PositionMs = position
+	u.player.UpdatedAt = time.Now()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resolveDevice returns the requested device, or the active one when no device is given
+func resolveDevice(u *user, deviceID string) (string, bool) {
+	for _, device := range u.devices {
+		if deviceID == "" && device.IsActive || deviceID != "" && device.ID == deviceID {
+			return device.ID, true
+		}
+	}
+	return "", false
+}
+
+func trackJSON(track Track) map[string]interface{} {
+	artists := make([]map[string]string, 0, len(track.Artists))
+	for _, name := range track.Artists {
+		artists = append(artists, map[string]string{"name": name})
+	}
+
+	images := []map[string]interface{}{}
+	if track.ArtworkURL != "" {
+		images = append(images, map[string]interface{}{"url": track.ArtworkURL, "width": 640, "height": 640})
+	}
+
+	return map[string]interface{}{
+		"name":        track.Name,
+		"artists":     artists,
+		"uri":         track.URI,
+		"duration_ms": track.DurationMs,
+		"explicit":    track.Explicit,
+		"popularity":  track.Popularity,
+		"album":       map[string]interface{}{"name": track.Album, "images": images},
+		"external_urls": map[string]string{
+			"spotify": "https://open.spotify.com/track/" + strings.TrimPrefix(track.URI, "spotify:track:"),
+		},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error in the Web API's format
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"status":  status,
+			"message": message,
+		},
+	})
+}
+
+// writeOAuthError writes an error in the accounts service's format
+func writeOAuthError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
@@ -0,0 +1,94 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrTokenNotFound is returned when a user has no stored Spotify token
+var ErrTokenNotFound = errors.New("user not authenticated with Spotify")
+
+// ErrSessionNotFound is returned when a channel has no jam session
+var ErrSessionNotFound = errors.New("no active session")
+
+// ErrSessionExists is returned when creating a session for a channel that already has one
+var ErrSessionExists = errors.New("a session already exists")
+
+// ErrNoPreferredDevice is returned when a user hasn't chosen a device
+var ErrNoPreferredDevice = errors.New("no preferred device")
+
+// ErrSessionConflict is returned when an update keeps losing to concurrent writers
+var ErrSessionConflict = errors.New("session was modified concurrently, please try again")
+
+// maxUpdateRetries bounds how often an optimistic session update is retried after losing a race
+const maxUpdateRetries = 100
+
+// updateRetryDelay returns a jittered delay that grows with the number of failed attempts
+func updateRetryDelay(attempt int) time.Duration {
+	ceiling := time.Duration(attempt+1) * time.Millisecond
+	if ceiling > 50*time.Millisecond {
+		ceiling = 50 * time.Millisecond
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)) + 1)
+}
+
+// SessionMutator changes a session in place. Returning an error aborts the update without saving.
+// It may be called more than once when the session is modified concurrently, so it must not have side effects.
+type SessionMutator func(session *Session) error
+
+// TokenStore persists users' Spotify OAuth tokens, keyed by Discord user ID
+type TokenStore interface {
+	// GetToken returns the stored token for a user, or ErrTokenNotFound
+	GetToken(ctx context.Context, userID string) (*oauth2.Token, error)
+	// SaveToken stores a user's token, replacing any previous one
+	SaveToken(ctx context.Context, userID string, token *oauth2.Token) error
+}
+
+// DevicePreference is the Spotify device a user chose to play jam sessions on.
+// The name is kept because some clients get a new device ID when they restart.
+type DevicePreference struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PreferenceStore persists per-user settings, keyed by Discord user ID, and per-guild settings, keyed by guild ID
+type PreferenceStore interface {
+	// GetPreferredDevice returns a user's chosen device, or ErrNoPreferredDevice
+	GetPreferredDevice(ctx context.Context, userID string) (*DevicePreference, error)
+	// SetPreferredDevice stores a user's chosen device, replacing any previous choice
+	SetPreferredDevice(ctx context.Context, userID string, device DevicePreference) error
+	// ClearPreferredDevice forgets a user's chosen device
+	ClearPreferredDevice(ctx context.Context, userID string) error
+
+	// GetQueuePolicy returns a guild's queue policy, or the zero policy if it has none
+	GetQueuePolicy(ctx context.Context, guildID string) (QueuePolicy, error)
+	// SetQueuePolicy stores a guild's queue policy, replacing any previous one
+	SetQueuePolicy(ctx context.Context, guildID string, policy QueuePolicy) error
+}
+
+// SessionStore persists jam sessions, keyed by channel ID, along with their skip votes
+type SessionStore interface {
+	// LoadSession returns the session for a channel, or an error wrapping ErrSessionNotFound
+	LoadSession(ctx context.Context, channelID string) (*Session, error)
+	// SaveSession stores a session, replacing any previous one for its channel
+	SaveSession(ctx context.Context, session *Session) error
+	// CreateSession stores a new session, or returns ErrSessionExists if its channel already has one
+	CreateSession(ctx context.Context, session *Session) error
+	// UpdateSession atomically applies fn to a channel's session, bumps its version and saves it.
+	// It returns the saved session, or an error wrapping ErrSessionNotFound or ErrSessionConflict.
+	UpdateSession(ctx context.Context, channelID string, fn SessionMutator) (*Session, error)
+	// DeleteSession atomically removes a channel's session and its skip votes and returns the session
+	// as it was, or an error wrapping ErrSessionNotFound or ErrSessionConflict
+	DeleteSession(ctx context.Context, channelID string) (*Session, error)
+	// LoadAllSessions returns every stored session
+	LoadAllSessions(ctx context.Context) ([]Session, error)
+
+	// AddSkipVote records a user's vote to skip a track and returns whether it is new and all voters so far
+	AddSkipVote(ctx context.Context, channelID, trackURI, userID string) (bool, []string, error)
+	// ClearSkipVotes removes all skip votes for a track
+	ClearSkipVotes(ctx context.Context, channelID, trackURI string) error
+}
@@ -0,0 +1,259 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// MemoryStore keeps tokens, preferences and sessions in process memory.
+// It is meant for tests and single-node development runs; nothing survives a restart.
+type MemoryStore struct {
+	mu        sync.Mutex
+	tokens    map[string]oauth2.Token
+	devices   map[string]DevicePreference
+	policies  map[string]QueuePolicy
+	sessions  map[string][]byte // channel ID -> JSON, so callers never share state with the store
+	skipVotes map[string]map[string]bool
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tokens:    make(map[string]oauth2.Token),
+		devices:   make(map[string]DevicePreference),
+		policies:  make(map[string]QueuePolicy),
+		sessions:  make(map[string][]byte),
+		skipVotes: make(map[string]map[string]bool),
+	}
+}
+
+// GetToken returns a copy of a user's token
+func (m *MemoryStore) GetToken(ctx context.Context, userID string) (*oauth2.Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[userID]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	return &token, nil
+}
+
+// SaveToken stores a copy of a user's token
+func (m *MemoryStore) SaveToken(ctx context.Context, userID string, token *oauth2.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[userID] = *token
+	return nil
+}
+
+// GetPreferredDevice returns a user's chosen device
+func (m *MemoryStore) GetPreferredDevice(ctx context.Context, userID string) (*DevicePreference, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	device, ok := m.devices[userID]
+	if !ok {
+		return nil, ErrNoPreferredDevice
+	}
+	return &device, nil
+}
+
+// SetPreferredDevice stores a user's chosen device
+func (m *MemoryStore) SetPreferredDevice(ctx context.Context, userID string, device DevicePreference) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.devices[userID] = device
+	return nil
+}
+
+// ClearPreferredDevice forgets a user's chosen device
+func (m *MemoryStore) ClearPreferredDevice(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.devices, userID)
+	return nil
+}
+
+// GetQueuePolicy returns a guild's queue policy
+func (m *MemoryStore) GetQueuePolicy(ctx context.Context, guildID string) (QueuePolicy, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.policies[guildID], nil
+}
+
+// SetQueuePolicy stores a guild's queue policy
+func (m *MemoryStore) SetQueuePolicy(ctx context.Context, guildID string, policy QueuePolicy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.policies[guildID] = policy
+	return nil
+}
+
+// LoadSession returns a copy of the session for a channel
+func (m *MemoryStore) LoadSession(ctx context.Context, channelID string) (*Session, error) {
+	m.mu.Lock()
+	sessionData, ok := m.sessions[channelID]
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w for channel %s", ErrSessionNotFound, channelID)
+	}
+
+	var session Session
+	err := json.Unmarshal(sessionData, &session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// SaveSession stores a copy of a session
+func (m *MemoryStore) SaveSession(ctx context.Context, session *Session) error {
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[session.ChannelID] = sessionData
+	return nil
+}
+
+// CreateSession stores a new session unless its channel already has one
+func (m *MemoryStore) CreateSession(ctx context.Context, session *Session) error {
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[session.ChannelID]; ok {
+		return fmt.Errorf("%w for channel %s", ErrSessionExists, session.ChannelID)
+	}
+	m.sessions[session.ChannelID] = sessionData
+	return nil
+}
+
+// UpdateSession applies fn to a session while holding the store lock
+func (m *MemoryStore) UpdateSession(ctx context.Context, channelID string, fn SessionMutator) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessionData, ok := m.sessions[channelID]
+	if !ok {
+		return nil, fmt.Errorf("%w for channel %s", ErrSessionNotFound, channelID)
+	}
+
+	var session Session
+	err := json.Unmarshal(sessionData, &session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	err = fn(&session)
+	if err != nil {
+		return nil, err
+	}
+	session.Version++
+
+	newData, err := json.Marshal(&session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	m.sessions[channelID] = newData
+
+	return &session, nil
+}
+
+// DeleteSession removes the session for a channel and its skip votes while holding the store lock
+func (m *MemoryStore) DeleteSession(ctx context.Context, channelID string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessionData, ok := m.sessions[channelID]
+	if !ok {
+		return nil, fmt.Errorf("%w for channel %s", ErrSessionNotFound, channelID)
+	}
+
+	var session Session
+	err := json.Unmarshal(sessionData, &session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	delete(m.sessions, channelID)
+	prefix := skipVoteKey(channelID, "")
+	for key := range m.skipVotes {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.skipVotes, key)
+		}
+	}
+
+	return &session, nil
+}
+
+// LoadAllSessions returns copies of every stored session
+func (m *MemoryStore) LoadAllSessions(ctx context.Context) ([]Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var sessions []Session
+	for channelID, sessionData := range m.sessions {
+		var session Session
+		err := json.Unmarshal(sessionData, &session)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal session for channel %s: %w", channelID, err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// AddSkipVote records a user's vote to skip a track
+func (m *MemoryStore) AddSkipVote(ctx context.Context, channelID, trackURI, userID string) (bool, []string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := skipVoteKey(channelID, trackURI)
+	votes, ok := m.skipVotes[key]
+	if !ok {
+		votes = make(map[string]bool)
+		m.skipVotes[key] = votes
+	}
+
+	added := !votes[userID]
+	votes[userID] = true
+
+	voters := make([]string, 0, len(votes))
+	for voter := range votes {
+		voters = append(voters, voter)
+	}
+
+	return added, voters, nil
+}
+
+// ClearSkipVotes removes all skip votes for a track
+func (m *MemoryStore) ClearSkipVotes(ctx context.Context, channelID, trackURI string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.skipVotes, skipVoteKey(channelID, trackURI))
+	return nil
+}
@@ -0,0 +1,367 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/oauth2"
+)
+
+// Session Key Prefix
+const sessionKeyPrefix = "jam_session_channel:" // Updated prefix
+
+// Device preference key prefix
+const devicePreferenceKeyPrefix = "jam_device_pref:"
+
+// Queue policy key prefix
+const queuePolicyKeyPrefix = "jam_queue_policy:"
+
+// tokenTTL is how long a stored Spotify token is kept without being refreshed
+const tokenTTL = time.Hour * 24 * 30
+
+// RedisStore keeps tokens, preferences and sessions in Redis
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a store backed by the given Redis client
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// GetToken retrieves a user's token from Redis
+func (r *RedisStore) GetToken(ctx context.Context, userID string) (*oauth2.Token, error) {
+	tokenData, err := r.client.Get(ctx, userID).Result()
+	if err == redis.Nil {
+		return nil, ErrTokenNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get token from Redis: %w", err)
+	}
+
+	var token oauth2.Token
+	err = json.Unmarshal([]byte(tokenData), &token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// SaveToken saves a user's token in Redis with the Discord user ID as key
+func (r *RedisStore) SaveToken(ctx context.Context, userID string, token *oauth2.Token) error {
+	tokenData, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	err = r.client.Set(ctx, userID, tokenData, tokenTTL).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save token to Redis: %w", err)
+	}
+
+	return nil
+}
+
+// GetPreferredDevice retrieves a user's chosen device from Redis
+func (r *RedisStore) GetPreferredDevice(ctx context.Context, userID string) (*DevicePreference, error) {
+	data, err := r.client.Get(ctx, devicePreferenceKeyPrefix+userID).Result()
+	if err == redis.Nil {
+		return nil, ErrNoPreferredDevice
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get device preference from Redis: %w", err)
+	}
+
+	var device DevicePreference
+	err = json.Unmarshal([]byte(data), &device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal device preference: %w", err)
+	}
+
+	return &device, nil
+}
+
+// SetPreferredDevice saves a user's chosen device in Redis
+func (r *RedisStore) SetPreferredDevice(ctx context.Context, userID string, device DevicePreference) error {
+	data, err := json.Marshal(device)
+	if err != nil {
+		return fmt.Errorf("failed to marshal device preference: %w", err)
+	}
+
+	err = r.client.Set(ctx, devicePreferenceKeyPrefix+userID, data, 0).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save device preference to Redis: %w", err)
+	}
+
+	return nil
+}
+
+// ClearPreferredDevice removes a user's chosen device from Redis
+func (r *RedisStore) ClearPreferredDevice(ctx context.Context, userID string) error {
+	return r.client.Del(ctx, devicePreferenceKeyPrefix+userID).Err()
+}
+
+// GetQueuePolicy retrieves a guild's queue policy from Redis
+func (r *RedisStore) GetQueuePolicy(ctx context.Context, guildID string) (QueuePolicy, error) {
+	data, err := r.client.Get(ctx, queuePolicyKeyPrefix+guildID).Result()
+	if err == redis.Nil {
+		return QueuePolicy{}, nil
+	} else if err != nil {
+		return QueuePolicy{}, fmt.Errorf("failed to get queue policy from Redis: %w", err)
+	}
+
+	var policy QueuePolicy
+	err = json.Unmarshal([]byte(data), &policy)
+	if err != nil {
+		return QueuePolicy{}, fmt.Errorf("failed to unmarshal queue policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// SetQueuePolicy saves a guild's queue policy in Redis
+func (r *RedisStore) SetQueuePolicy(ctx context.Context, guildID string, policy QueuePolicy) error {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queue policy: %w", err)
+	}
+
+	err = r.client.Set(ctx, queuePolicyKeyPrefix+guildID, data, 0).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save queue policy to Redis: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSession loads a jam session from Redis based on ChannelID
+func (r *RedisStore) LoadSession(ctx context.Context, channelID string) (*Session, error) {
+	key := fmt.Sprintf("%s%s", sessionKeyPrefix, channelID)
+	sessionData, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("%w for channel %s", ErrSessionNotFound, channelID)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get session from Redis: %w", err)
+	}
+
+	var session Session
+	err = json.Unmarshal([]byte(sessionData), &session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// SaveSession saves a jam session to Redis based on ChannelID
+func (r *RedisStore) SaveSession(ctx context.Context, session *Session) error {
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	key := fmt.Sprintf("%s%s", sessionKeyPrefix, session.ChannelID)
+	return r.client.Set(ctx, key, sessionData, 0).Err()
+}
+
+// CreateSession saves a new jam session to Redis unless the channel already has one
+func (r *RedisStore) CreateSession(ctx context.Context, session *Session) error {
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	key := fmt.Sprintf("%s%s", sessionKeyPrefix, session.ChannelID)
+	created, err := r.client.SetNX(ctx, key, sessionData, 0).Result()
+	if err != nil {
+		return fmt.Errorf("failed to create session in Redis: %w", err)
+	}
+	if !created {
+		return fmt.Errorf("%w for channel %s", ErrSessionExists, session.ChannelID)
+	}
+
+	return nil
+}
+
+// UpdateSession applies fn to a session inside a WATCH/MULTI transaction, retrying when another writer wins the race
+func (r *RedisStore) UpdateSession(ctx context.Context, channelID string, fn SessionMutator) (*Session, error) {
+	key := fmt.Sprintf("%s%s", sessionKeyPrefix, channelID)
+
+	var updated *Session
+	txf := func(tx *redis.Tx) error {
+		sessionData, err := tx.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return fmt.Errorf("%w for channel %s", ErrSessionNotFound, channelID)
+		} else if err != nil {
+			return fmt.Errorf("failed to get session from Redis: %w", err)
+		}
+
+		var session Session
+		err = json.Unmarshal([]byte(sessionData), &session)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal session: %w", err)
+		}
+
+		err = fn(&session)
+		if err != nil {
+			return err
+		}
+		session.Version++
+
+		newData, err := json.Marshal(&session)
+		if err != nil {
+			return fmt.Errorf("failed to marshal session: %w", err)
+		}
+
+		// The write only goes through if nobody touched the key since WATCH
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, newData, 0)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		updated = &session
+		return nil
+	}
+
+	for attempt := 0; attempt < maxUpdateRetries; attempt++ {
+		err := r.client.Watch(ctx, txf, key)
+		if err == nil {
+			return updated, nil
+		}
+		if err != redis.TxFailedErr {
+			return nil, err
+		}
+
+		// Lost the race to another writer; back off before retrying
+		select {
+		case <-time.After(updateRetryDelay(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, fmt.Errorf("%w (channel %s)", ErrSessionConflict, channelID)
+}
+
+// DeleteSession deletes a jam session and its skip votes from Redis in one transaction, using WATCH
+// so a change made while the session is being read can't be lost
+func (r *RedisStore) DeleteSession(ctx context.Context, channelID string) (*Session, error) {
+	key := fmt.Sprintf("%s%s", sessionKeyPrefix, channelID)
+
+	var deleted *Session
+	txf := func(tx *redis.Tx) error {
+		sessionData, err := tx.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return fmt.Errorf("%w for channel %s", ErrSessionNotFound, channelID)
+		} else if err != nil {
+			return fmt.Errorf("failed to get session from Redis: %w", err)
+		}
+
+		var session Session
+		err = json.Unmarshal([]byte(sessionData), &session)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal session: %w", err)
+		}
+
+		keys := []string{key}
+		iter := tx.Scan(ctx, 0, skipVoteKey(channelID, "")+"*", 0).Iterator()
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("failed to scan skip votes in Redis: %w", err)
+		}
+
+		// The delete only goes through if nobody touched the session since WATCH
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, keys...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		deleted = &session
+		return nil
+	}
+
+	for attempt := 0; attempt < maxUpdateRetries; attempt++ {
+		err := r.client.Watch(ctx, txf, key)
+		if err == nil {
+			return deleted, nil
+		}
+		if err != redis.TxFailedErr {
+			return nil, err
+		}
+
+		// Lost the race to another writer; back off before retrying
+		select {
+		case <-time.After(updateRetryDelay(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, fmt.Errorf("%w (channel %s)", ErrSessionConflict, channelID)
+}
+
+// LoadAllSessions retrieves all active jam sessions from Redis
+func (r *RedisStore) LoadAllSessions(ctx context.Context) ([]Session, error) {
+	var sessions []Session
+	iter := r.client.Scan(ctx, 0, fmt.Sprintf("%s*", sessionKeyPrefix), 0).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		sessionData, err := r.client.Get(ctx, key).Result()
+		if err != nil {
+			log.Printf("[ERROR] Failed to get session data for key %s: %v", key, err)
+			continue
+		}
+
+		var session Session
+		err = json.Unmarshal([]byte(sessionData), &session)
+		if err != nil {
+			log.Printf("[ERROR] Failed to unmarshal session data for key %s: %v", key, err)
+			continue
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating Redis keys: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// AddSkipVote adds a user to the Redis set of skip votes for a track
+func (r *RedisStore) AddSkipVote(ctx context.Context, channelID, trackURI, userID string) (bool, []string, error) {
+	key := skipVoteKey(channelID, trackURI)
+	added, err := r.client.SAdd(ctx, key, userID).Result()
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to save skip vote to Redis: %w", err)
+	}
+
+	err = r.client.Expire(ctx, key, skipVoteTTL).Err()
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to set skip vote expiry: %w", err)
+	}
+
+	voters, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get skip votes from Redis: %w", err)
+	}
+
+	return added > 0, voters, nil
+}
+
+// ClearSkipVotes deletes the Redis set of skip votes for a track
+func (r *RedisStore) ClearSkipVotes(ctx context.Context, channelID, trackURI string) error {
+	return r.client.Del(ctx, skipVoteKey(channelID, trackURI)).Err()
+}
@@ -0,0 +1,153 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"jam-bot/internal/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// concurrentAdds is how many songs the concurrency tests add at once
+const concurrentAdds = 300
+
+// testConcurrentAdds adds songs to one session from many goroutines and checks that no update was lost
+func testConcurrentAdds(t *testing.T, store interface {
+	TokenStore
+	PreferenceStore
+	SessionStore
+}) {
+	t.Helper()
+	ctx := context.Background()
+	s := NewSpotifyServiceWithStores(&config.Config{}, store, store, store, nil)
+
+	_, err := s.StartSession(ctx, "guild", "channel", "host", SessionSettings{})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrentAdds)
+	for i := 0; i < concurrentAdds; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			song := Song{Title: fmt.Sprintf("Song %d", i), URI: fmt.Sprintf("spotify:track:%d", i)}
+			errs <- s.AddSongToQueue(ctx, "channel", song)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AddSongToQueue: %v", err)
+		}
+	}
+
+	session, err := s.LoadSession(ctx, "channel")
+	if err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	if len(session.Queue) != concurrentAdds {
+		t.Errorf("queue has %d songs, want %d", len(session.Queue), concurrentAdds)
+	}
+	if session.Version != concurrentAdds {
+		t.Errorf("session version is %d, want %d", session.Version, concurrentAdds)
+	}
+
+	// Every add got its own sequence number, so none of them read a stale session
+	seen := make(map[int64]bool)
+	for _, song := range session.Queue {
+		if seen[song.Seq] {
+			t.Errorf("sequence number %d was given out twice", song.Seq)
+		}
+		seen[song.Seq] = true
+	}
+}
+
+func TestMemoryStoreConcurrentAdds(t *testing.T) {
+	testConcurrentAdds(t, NewMemoryStore())
+}
+
+func TestRedisStoreConcurrentAdds(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	testConcurrentAdds(t, NewRedisStore(client))
+}
+
+// testDeleteSession checks that deleting a session returns it and takes its skip votes with it
+func testDeleteSession(t *testing.T, store SessionStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	session := &Session{ChannelID: "channel", Queue: []Song{{Title: "Song", URI: "spotify:track:song"}}}
+	err := store.CreateSession(ctx, session)
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	for _, vote := range []struct{ channelID, trackURI string }{
+		{"channel", "spotify:track:song"},
+		{"channel", "spotify:track:other"},
+		{"other-channel", "spotify:track:song"},
+	} {
+		_, _, err = store.AddSkipVote(ctx, vote.channelID, vote.trackURI, "voter")
+		if err != nil {
+			t.Fatalf("AddSkipVote: %v", err)
+		}
+	}
+
+	deleted, err := store.DeleteSession(ctx, "channel")
+	if err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+	if len(deleted.Queue) != 1 || deleted.Queue[0].URI != "spotify:track:song" {
+		t.Errorf("DeleteSession returned queue %v, want the stored song", deleted.Queue)
+	}
+
+	_, err = store.LoadSession(ctx, "channel")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("LoadSession after delete: %v, want ErrSessionNotFound", err)
+	}
+	_, err = store.DeleteSession(ctx, "channel")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("second DeleteSession: %v, want ErrSessionNotFound", err)
+	}
+
+	// The same vote counts as new again once the session's votes are gone, but other channels keep theirs
+	for _, tt := range []struct {
+		channelID, trackURI string
+		wantNew             bool
+	}{
+		{"channel", "spotify:track:song", true},
+		{"channel", "spotify:track:other", true},
+		{"other-channel", "spotify:track:song", false},
+	} {
+		added, _, err := store.AddSkipVote(ctx, tt.channelID, tt.trackURI, "voter")
+		if err != nil {
+			t.Fatalf("AddSkipVote: %v", err)
+		}
+		if added != tt.wantNew {
+			t.Errorf("vote in %s for %s is new: %v, want %v", tt.channelID, tt.trackURI, added, tt.wantNew)
+		}
+	}
+}
+
+func TestMemoryStoreDeleteSession(t *testing.T) {
+	testDeleteSession(t, NewMemoryStore())
+}
+
+func TestRedisStoreDeleteSession(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	testDeleteSession(t, NewRedisStore(client))
+}
@@ -0,0 +1,282 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"jam-bot/internal/spotify/api"
+)
+
+// syncWarmup skips measuring right after playback (re)starts, while devices are still buffering
+const syncWarmup = 3 * time.Second
+
+// errNotFollowing is reported for listeners who are not playing the session's song
+var errNotFollowing = errors.New("not playing the session's song")
+
+// DriftStats describes how far a listener has drifted from the session over time
+type DriftStats struct {
+	Samples        int       // Measurements taken
+	Corrections    int       // Seeks issued to bring the listener back in line
+	LastDriftMs    int       // Most recent drift; positive means ahead of the session
+	MeanAbsDriftMs int       // Average size of the drift across all samples
+	MaxAbsDriftMs  int       // Largest drift seen
+	LatencyMs      int       // Round trip of the most recent measurement
+	LastSampleAt   time.Time // When the most recent measurement was taken
+	totalAbsDrift  int64
+}
+
+// SyncEngine runs a synchronization loop for every playing session and keeps drift statistics per listener
+type SyncEngine struct {
+	mu        sync.Mutex
+	interval  time.Duration
+	tolerance time.Duration
+	loops     map[string]chan struct{}          // channel ID -> quit channel of its loop
+	stats     map[string]map[string]*DriftStats // channel ID -> user ID -> stats
+	onTick    func(channelID string) bool       // Runs one pass; returns false once the session no longer needs syncing
+}
+
+// NewSyncEngine creates a sync engine that calls onTick every interval for each started session.
+// A non-positive interval disables the loops.
+func NewSyncEngine(interval, tolerance time.Duration, onTick func(channelID string) bool) *SyncEngine {
+	return &SyncEngine{
+		interval:  interval,
+		tolerance: tolerance,
+		loops:     make(map[string]chan struct{}),
+		stats:     make(map[string]map[string]*DriftStats),
+		onTick:    onTick,
+	}
+}
+
+// Start begins synchronizing a session, if it isn't already
+func (e *SyncEngine) Start(channelID string) {
+	if e.interval <= 0 {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.loops[channelID]; ok {
+		return
+	}
+
+	quit := make(chan struct{})
+	e.loops[channelID] = quit
+	go e.run(channelID, quit)
+}
+
+// Stop ends a session's synchronization loop, keeping its statistics
+func (e *SyncEngine) Stop(channelID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stopLocked(channelID)
+}
+
+// Forget ends a session's synchronization loop and drops its statistics
+func (e *SyncEngine) Forget(channelID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stopLocked(channelID)
+	delete(e.stats, channelID)
+}
+
+// Stats returns a copy of the drift statistics for each listener in a session
+func (e *SyncEngine) Stats(channelID string) map[string]DriftStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	stats := make(map[string]DriftStats, len(e.stats[channelID]))
+	for userID, s := range e.stats[channelID] {
+		stats[userID] = *s
+	}
+	return stats
+}
+
+// record adds a drift measurement to a listener's statistics
+func (e *SyncEngine) record(channelID, userID string, driftMs int, latency time.Duration, corrected bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	users, ok := e.stats[channelID]
+	if !ok {
+		users = make(map[string]*DriftStats)
+		e.stats[channelID] = users
+	}
+	s, ok := users[userID]
+	if !ok {
+		s = &DriftStats{}
+		users[userID] = s
+	}
+
+	absDrift := driftMs
+	if absDrift < 0 {
+		absDrift = -absDrift
+	}
+
+	s.Samples++
+	if corrected {
+		s.Corrections++
+	}
+	s.LastDriftMs = driftMs
+	s.totalAbsDrift += int64(absDrift)
+	s.MeanAbsDriftMs = int(s.totalAbsDrift / int64(s.Samples))
+	if absDrift > s.MaxAbsDriftMs {
+		s.MaxAbsDriftMs = absDrift
+	}
+	s.LatencyMs = int(latency.Milliseconds())
+	s.LastSampleAt = time.Now()
+}
+
+// run ticks a session's synchronization until it is stopped or no longer needed
+func (e *SyncEngine) run(channelID string, quit chan struct{}) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+			if e.onTick(channelID) {
+				continue
+			}
+
+			e.mu.Lock()
+			// Only remove the loop if it hasn't been replaced in the meantime
+			if e.loops[channelID] == quit {
+				delete(e.loops, channelID)
+			}
+			e.mu.Unlock()
+			return
+		}
+	}
+}
+
+// stopLocked stops and forgets a session's loop; e.mu must be held
+func (e *SyncEngine) stopLocked(channelID string) {
+	if quit, ok := e.loops[channelID]; ok {
+		close(quit)
+		delete(e.loops, channelID)
+	}
+}
+
+// SyncStats returns the drift statistics for each listener in a session
+func (s *Service) SyncStats(channelID string) map[string]DriftStats {
+	return s.syncer.Stats(channelID)
+}
+
+// SyncTolerance returns how far a listener may drift before being corrected
+func (s *Service) SyncTolerance() time.Duration {
+	return s.syncer.tolerance
+}
+
+// syncTick runs one synchronization pass for the sync engine
+func (s *Service) syncTick(channelID string) bool {
+	ctx := context.Background()
+
+	session, err := s.LoadSession(ctx, channelID)
+	if errors.Is(err, ErrSessionNotFound) {
+		return false
+	}
+	if err != nil {
+		log.Printf("[WARN] Failed to load session %s for synchronization: %v", channelID, err)
+		return true
+	}
+
+	if !session.Playback.IsPlaying {
+		return false
+	}
+
+	// Devices report unreliable progress while they are still starting the song
+	if time.Since(session.Playback.UpdatedAt()) < syncWarmup {
+		return true
+	}
+
+	report, err := s.SynchronizePlayback(ctx, channelID)
+	if err != nil {
+		log.Printf("[WARN] Failed to synchronize playback for channel %s: %v", channelID, err)
+		return true
+	}
+
+	for _, result := range report.Failed() {
+		if !errors.Is(result.Err, errNotFollowing) {
+			log.Printf("[WARN] Failed to synchronize user %s in channel %s: %v", result.UserID, channelID, result.Err)
+		}
+	}
+
+	return true
+}
+
+// SynchronizePlayback measures how far each listener has drifted from the session and seeks
+// only those beyond the tolerance. Listeners playing something else are left alone.
+func (s *Service) SynchronizePlayback(ctx context.Context, channelID string) (PlaybackReport, error) {
+	session, err := s.LoadSession(ctx, channelID)
+	if err != nil {
+		return PlaybackReport{}, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	if !session.Playback.IsPlaying {
+		return PlaybackReport{}, nil // No need to synchronize if not playing
+	}
+
+	playback := session.Playback
+	report := PlaybackReport{
+		Song:       playback.CurrentSong,
+		PositionMs: playback.PositionAt(time.Now()),
+	}
+
+	report.Results = s.fanOutClients(ctx, session.Participants, func(ctx context.Context, userID string, client *api.Client) (string, error) {
+		return s.syncListener(ctx, channelID, userID, playback, client)
+	})
+
+	return report, nil
+}
+
+// syncListener measures one listener's drift and seeks their device if it exceeds the tolerance
+func (s *Service) syncListener(ctx context.Context, channelID, userID string, playback PlaybackState, client *api.Client) (string, error) {
+	sent := time.Now()
+	state, err := client.PlayerState(ctx)
+	if err != nil {
+		return "", err
+	}
+	latency := time.Since(sent)
+
+	if state == nil || state.Device == nil {
+		return "", errNotFollowing
+	}
+	if !state.IsPlaying || state.Item == nil || state.Item.URI != playback.CurrentSong.URI {
+		return state.Device.Name, errNotFollowing
+	}
+
+	// Spotify sampled the progress somewhere in the round trip; assume the middle
+	sampledAt := sent.Add(latency / 2)
+	drift := state.ProgressMs - playback.PositionAt(sampledAt)
+
+	tolerance := int(s.syncer.tolerance.Milliseconds())
+	if drift <= tolerance && drift >= -tolerance {
+		s.syncer.record(channelID, userID, drift, latency, false)
+		return state.Device.Name, nil
+	}
+
+	// The seek takes effect about half a round trip after it is sent
+	target := playback.PositionAt(time.Now().Add(latency / 2))
+	if playback.CurrentSong.DurationMs > 0 && target >= playback.CurrentSong.DurationMs-tolerance {
+		// The song is about to end; the scheduler will start the next one for everyone
+		s.syncer.record(channelID, userID, drift, latency, false)
+		return state.Device.Name, nil
+	}
+
+	err = client.Seek(ctx, state.Device.ID, target)
+	if err != nil {
+		return state.Device.Name, err
+	}
+
+	s.syncer.record(channelID, userID, drift, latency, true)
+	return state.Device.Name, nil
+}
@@ -0,0 +1,120 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Skip vote key prefix; votes are kept per channel and per track URI
+const skipVoteKeyPrefix = "jam_skip_votes:"
+
+// defaultVoteSkipThreshold is used when the configured threshold is out of range
+const defaultVoteSkipThreshold = 0.5
+
+// skipVoteTTL lets the store clean up votes for tracks that were never skipped
+const skipVoteTTL = 6 * time.Hour
+
+// errStaleVote aborts a vote skip whose song was already skipped, by another vote or otherwise
+var errStaleVote = errors.New("the voted song is no longer playing")
+
+// SkipVoteTally describes the state of the skip vote for the current song
+type SkipVoteTally struct {
+	Song         Song // The song being voted on
+	Votes        int  // Votes from current participants
+	Required     int  // Votes needed to skip
+	AlreadyVoted bool // The voter had already voted for this song
+	Skipped      bool // The vote passed and the song was skipped
+	NextSong     Song // The song now playing, or up next when paused, if the vote passed and something is left
+	// Report describes starting the next song, if the vote passed
+	Report PlaybackReport
+}
+
+func skipVoteKey(channelID, trackURI string) string {
+	return fmt.Sprintf("%s%s:%s", skipVoteKeyPrefix, channelID, trackURI)
+}
+
+// requiredSkipVotes returns how many of the given participants must agree to skip a song
+func (s *Service) requiredSkipVotes(participants int) int {
+	required := int(math.Ceil(s.voteSkipThreshold * float64(participants)))
+	if required < 1 {
+		required = 1
+	}
+	return required
+}
+
+// CastSkipVote records a participant's vote to skip the current song and skips it once enough participants agree
+func (s *Service) CastSkipVote(ctx context.Context, channelID, userID string) (SkipVoteTally, error) {
+	session, err := s.LoadSession(ctx, channelID)
+	if err != nil {
+		return SkipVoteTally{}, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	currentSong := session.Playback.CurrentSong
+	if currentSong.URI == "" {
+		return SkipVoteTally{}, fmt.Errorf("nothing is playing right now")
+	}
+
+	isParticipant := false
+	for _, id := range session.Participants {
+		if id == userID {
+			isParticipant = true
+			break
+		}
+	}
+	if !isParticipant {
+		return SkipVoteTally{}, fmt.Errorf("only participants in the jam session can vote")
+	}
+
+	added, voters, err := s.sessions.AddSkipVote(ctx, channelID, currentSong.URI, userID)
+	if err != nil {
+		return SkipVoteTally{}, err
+	}
+
+	// Only count votes from users who are still in the session
+	votes := 0
+	for _, voter := range voters {
+		for _, id := range session.Participants {
+			if voter == id {
+				votes++
+				break
+			}
+		}
+	}
+
+	tally := SkipVoteTally{
+		Song:         currentSong,
+		Votes:        votes,
+		Required:     s.requiredSkipVotes(len(session.Participants)),
+		AlreadyVoted: !added,
+	}
+
+	if tally.Votes < tally.Required {
+		return tally, nil
+	}
+
+	report, err := s.skipSong(ctx, channelID, currentSong.URI)
+	if errors.Is(err, errStaleVote) {
+		// A concurrent vote already skipped the song; report what plays now
+		tally.Skipped = true
+		if session, loadErr := s.LoadSession(ctx, channelID); loadErr == nil {
+			tally.NextSong = session.Playback.CurrentSong
+		}
+		return tally, nil
+	}
+	if err != nil {
+		return tally, err
+	}
+
+	tally.Skipped = true
+	tally.NextSong = report.Song
+	tally.Report = report
+	return tally, nil
+}
+
+// ClearSkipVotes removes all skip votes for a track in a channel
+func (s *Service) ClearSkipVotes(ctx context.Context, channelID, trackURI string) error {
+	return s.sessions.ClearSkipVotes(ctx, channelID, trackURI)
+}